@@ -0,0 +1,98 @@
+// Command addmember creates a member who can log in and adds them to a
+// group with a role. The password is read from the first line of standard
+// input. If a member with the email already exists, they are only added to
+// the group, or get the new role if they already belong to it. With -player,
+// the member is linked to their player in the group, so they can answer RSVPs
+// for themselves.
+//
+//	echo 'secret password' | go run ./cmd/addmember -email anna@example.com -name Anna -group hans -role admin -player 3
+package main
+
+import (
+	"bufio"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/klausbreyer/pokerhans/internal/config"
+	"github.com/klausbreyer/pokerhans/internal/db"
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+func main() {
+	// Set up logger
+	logger := log.New(os.Stdout, "addmember: ", log.LstdFlags)
+
+	email := flag.String("email", "", "email the member logs in with")
+	name := flag.String("name", "", "name shown to others")
+	slug := flag.String("group", models.DefaultGroupSlug, "slug of the group to add the member to")
+	role := flag.String("role", string(models.RoleMember), "role in the group: admin, member or viewer")
+	playerID := flag.Int("player", 0, "ID of the member's own player in the group, if any")
+	flag.Parse()
+
+	if *email == "" {
+		logger.Fatalf("Missing -email")
+	}
+	if !models.Role(*role).IsValid() {
+		logger.Fatalf("Invalid -role %q: %v", *role, models.ErrInvalidRole)
+	}
+
+	// Load environment variables from .env file
+	envPath := ".env"
+	if err := config.LoadEnv(envPath); err != nil {
+		logger.Printf("Warning: Unable to load .env file: %v", err)
+	}
+
+	// Connect to database
+	database, err := db.Connect()
+	if err != nil {
+		logger.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	repo := models.NewRepository(database)
+
+	group, err := repo.GetGroupBySlug(*slug)
+	if err != nil {
+		logger.Fatalf("Failed to find group %s: %v", *slug, err)
+	}
+
+	member, err := repo.GetMemberByEmail(*email)
+	if errors.Is(err, sql.ErrNoRows) {
+		if *name == "" {
+			logger.Fatalf("Missing -name for the new member")
+		}
+
+		fmt.Fprint(os.Stderr, "Password: ")
+		password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && password == "" {
+			logger.Fatalf("Failed to read password: %v", err)
+		}
+
+		id, err := repo.CreateMember(*email, *name, strings.TrimRight(password, "\r\n"))
+		if err != nil {
+			logger.Fatalf("Failed to create member: %v", err)
+		}
+		member.ID = id
+		fmt.Printf("Created member %s (ID: %d)\n", models.NormalizeEmail(*email), id)
+	} else if err != nil {
+		logger.Fatalf("Failed to look up member: %v", err)
+	}
+
+	groupRepo := repo.ForGroup(group.ID)
+	if err := groupRepo.AddGroupMember(member.ID, models.Role(*role)); err != nil {
+		logger.Fatalf("Failed to add member to group: %v", err)
+	}
+	fmt.Printf("Member %s is %s of group %s\n", models.NormalizeEmail(*email), *role, group.Name)
+
+	if *playerID != 0 {
+		if err := groupRepo.SetMemberPlayer(member.ID, *playerID); err != nil {
+			logger.Fatalf("Failed to link member to player %d: %v", *playerID, err)
+		}
+		fmt.Printf("Member %s answers as player %d\n", models.NormalizeEmail(*email), *playerID)
+	}
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"database/sql"
 	"fmt"
 	"log"
 	"math/rand"
@@ -10,6 +9,7 @@ import (
 
 	"github.com/klausbreyer/pokerhans/internal/config"
 	"github.com/klausbreyer/pokerhans/internal/db"
+	"github.com/klausbreyer/pokerhans/internal/models"
 )
 
 func main() {
@@ -29,6 +29,12 @@ func main() {
 	}
 	defer database.Close()
 
+	group, err := models.NewRepository(database).GetGroupBySlug(models.DefaultGroupSlug)
+	if err != nil {
+		logger.Fatalf("Failed to find group %s: %v", models.DefaultGroupSlug, err)
+	}
+	repo := models.NewRepository(database).ForGroup(group.ID)
+
 	// Seed the random number generator
 	rand.Seed(time.Now().UnixNano())
 
@@ -41,21 +47,21 @@ func main() {
 	}
 
 	seasonIDs := make(map[string]int)
-	for _, seasonName := range seasons {
+	for i, seasonName := range seasons {
 		// Add season
-		_, err = database.Exec("INSERT INTO seasons (name) VALUES (?)", seasonName)
+		seasonID, err := repo.CreateSeason(seasonName, nil, nil)
 		if err != nil {
 			logger.Fatalf("Failed to add season %s: %v", seasonName, err)
 		}
-
-		// Get the season ID
-		var seasonID int
-		err = database.QueryRow("SELECT id FROM seasons WHERE name = ?", seasonName).Scan(&seasonID)
-		if err != nil {
-			logger.Fatalf("Failed to get season ID for %s: %v", seasonName, err)
-		}
 		seasonIDs[seasonName] = seasonID
 		fmt.Printf("Created season: %s (ID: %d)\n", seasonName, seasonID)
+
+		// Only the most recent season stays active
+		if i < len(seasons)-1 {
+			if err := repo.ArchiveSeason(seasonID); err != nil {
+				logger.Fatalf("Failed to archive season %s: %v", seasonName, err)
+			}
+		}
 	}
 
 	// Create players with more German poker-themed names
@@ -69,17 +75,11 @@ func main() {
 	playerIDs := make(map[string]int)
 	for _, name := range playerNames {
 		// Add player
-		result, err := database.Exec("INSERT INTO players (name) VALUES (?)", name)
+		playerID, err := repo.CreatePlayer(name)
 		if err != nil {
 			logger.Fatalf("Failed to add player %s: %v", name, err)
 		}
-
-		// Get player ID
-		playerID, err := result.LastInsertId()
-		if err != nil {
-			logger.Fatalf("Failed to get player ID for %s: %v", name, err)
-		}
-		playerIDs[name] = int(playerID)
+		playerIDs[name] = playerID
 		fmt.Printf("Created player: %s (ID: %d)\n", name, playerID)
 	}
 
@@ -98,25 +98,26 @@ func main() {
 		// Take the first numPlayers players
 		seasonPlayers := shuffledPlayers[:numPlayers]
 
-		for _, playerName := range seasonPlayers {
-			playerID := playerIDs[playerName]
-			// Add player to season
-			_, err = database.Exec("INSERT INTO season_players (season_id, player_id) VALUES (?, ?)", seasonID, playerID)
-			if err != nil {
-				logger.Printf("Warning: Failed to add player %s to season %s: %v", playerName, seasonName, err)
-				continue
-			}
-			fmt.Printf("Added player %s to season %s\n", playerName, seasonName)
+		rosterIDs := make([]int, len(seasonPlayers))
+		for i, playerName := range seasonPlayers {
+			rosterIDs[i] = playerIDs[playerName]
+		}
+
+		// Add players to the season roster
+		if err := repo.SetSeasonRoster(seasonID, rosterIDs); err != nil {
+			logger.Printf("Warning: Failed to set roster for season %s: %v", seasonName, err)
+		} else {
+			fmt.Printf("Added %d players to season %s\n", len(seasonPlayers), seasonName)
 		}
 
 		// Create games for this season
-		createGamesForSeason(database, logger, seasonID, seasonName, seasonPlayers, playerIDs)
+		createGamesForSeason(repo, logger, seasonID, seasonName, seasonPlayers, playerIDs)
 	}
 
 	fmt.Println("Successfully generated demo data!")
 }
 
-func createGamesForSeason(database *sql.DB, logger *log.Logger, seasonID int, seasonName string, seasonPlayers []string, playerIDs map[string]int) {
+func createGamesForSeason(repo *models.Repository, logger *log.Logger, seasonID int, seasonName string, seasonPlayers []string, playerIDs map[string]int) {
 	// Determine the start date for the season
 	var startDate time.Time
 	switch seasonName {
@@ -132,40 +133,43 @@ func createGamesForSeason(database *sql.DB, logger *log.Logger, seasonID int, se
 		startDate = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
 	}
 
-	// Create between 10-20 games for the season
-	numGames := rand.Intn(11) + 10 // 10-20 games
+	// Every player hosts at most once per season, so pick the hosts up front
+	hosts := make([]string, len(seasonPlayers))
+	copy(hosts, seasonPlayers)
+	rand.Shuffle(len(hosts), func(i, j int) {
+		hosts[i], hosts[j] = hosts[j], hosts[i]
+	})
+
+	// Create between 8 games and one per player for the season
+	numGames := rand.Intn(len(hosts)-7) + 8
 
 	for i := 0; i < numGames; i++ {
 		// Select random date for the game (within a 3-month period from start date)
 		gameDate := startDate.Add(time.Duration(rand.Intn(90)) * 24 * time.Hour)
 
-		// Select random host, winner, and second place
+		hostName := hosts[i]
+		hostID := playerIDs[hostName]
+
+		// Select random participants for the finishing order
 		rand.Shuffle(len(seasonPlayers), func(i, j int) {
 			seasonPlayers[i], seasonPlayers[j] = seasonPlayers[j], seasonPlayers[i]
 		})
 
-		hostName := seasonPlayers[0]
-		hostID := playerIDs[hostName]
-
-		// Some games might not have winners recorded yet (about 20% chance)
-		var winnerID, secondPlaceID *int
+		// Some games might not have results recorded yet (about 20% chance)
+		var finishingOrder []int
 		if rand.Float32() > 0.2 {
-			// Ensure winner and second place are different from host and each other
-			winnerName := seasonPlayers[1]
-			secondPlaceName := seasonPlayers[2]
-
-			winnerIDValue := playerIDs[winnerName]
-			secondPlaceIDValue := playerIDs[secondPlaceName]
-
-			winnerID = &winnerIDValue
-			secondPlaceID = &secondPlaceIDValue
+			// Between 4 and 8 players take part, in the shuffled order
+			numParticipants := rand.Intn(5) + 4
+			for _, name := range seasonPlayers[:numParticipants] {
+				finishingOrder = append(finishingOrder, playerIDs[name])
+			}
+			rand.Shuffle(len(finishingOrder), func(i, j int) {
+				finishingOrder[i], finishingOrder[j] = finishingOrder[j], finishingOrder[i]
+			})
 		}
 
 		// Add the game
-		_, err := database.Exec(
-			"INSERT INTO games (season_id, host_id, winner_id, second_place_id, game_date) VALUES (?, ?, ?, ?, ?)",
-			seasonID, hostID, winnerID, secondPlaceID, gameDate.Format("2006-01-02"),
-		)
+		err := repo.AddGame(seasonID, hostID, gameDate, finishingOrder, demoLedger(finishingOrder))
 		if err != nil {
 			logger.Printf("Warning: Failed to create game for %s on %s: %v",
 				hostName, gameDate.Format("2006-01-02"), err)
@@ -174,14 +178,12 @@ func createGamesForSeason(database *sql.DB, logger *log.Logger, seasonID int, se
 
 		winnerStr := "unknown"
 		secondStr := "unknown"
-		if winnerID != nil {
-			for name, id := range playerIDs {
-				if id == *winnerID {
-					winnerStr = name
-				}
-				if secondPlaceID != nil && id == *secondPlaceID {
-					secondStr = name
-				}
+		for name, id := range playerIDs {
+			if len(finishingOrder) > 0 && id == finishingOrder[0] {
+				winnerStr = name
+			}
+			if len(finishingOrder) > 1 && id == finishingOrder[1] {
+				secondStr = name
 			}
 		}
 
@@ -189,3 +191,30 @@ func createGamesForSeason(database *sql.DB, logger *log.Logger, seasonID int, se
 			seasonName, gameDate.Format("2006-01-02"), hostName, winnerStr, secondStr)
 	}
 }
+
+// demoLedger creates a ledger with a 10 euro buy-in per participant, some
+// random rebuys and a 60/30/10 split of the pot among the top three
+func demoLedger(finishingOrder []int) []models.LedgerEntry {
+	if len(finishingOrder) < 3 {
+		return nil
+	}
+
+	entries := make([]models.LedgerEntry, len(finishingOrder))
+	var pot models.Cents
+	for i, playerID := range finishingOrder {
+		entries[i] = models.LedgerEntry{
+			PlayerID: playerID,
+			BuyIn:    1000,
+			Rebuys:   models.Cents(rand.Intn(3) * 500),
+		}
+		pot += entries[i].BuyIn + entries[i].Rebuys
+	}
+
+	second := pot * 30 / 100
+	third := pot * 10 / 100
+	entries[0].Payout = pot - second - third
+	entries[1].Payout = second
+	entries[2].Payout = third
+
+	return entries
+}
@@ -0,0 +1,222 @@
+// Command fixgames prepares a database for the game constraints of migration
+// 000011. It lists games whose winner is also second place and games of a
+// host who hosted more than once in a season. Later copies of a game are
+// double submissions if their date, places and finishing order match an
+// earlier game and they have no ledger or settlement payments of their own.
+//
+// Without -apply nothing is changed. With -apply, the second place equal to
+// the winner is cleared and double submissions are deleted in one
+// transaction. If any other game shares a season and host, nothing is changed
+// at all, as those are real games that have to be fixed by hand.
+//
+//	go run ./cmd/fixgames -apply
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/klausbreyer/pokerhans/internal/config"
+	"github.com/klausbreyer/pokerhans/internal/db"
+)
+
+// game is a row of the games table with the columns every schema version has
+type game struct {
+	id           int
+	seasonID     int
+	hostID       int
+	gameDate     string
+	winnerID     sql.NullInt64
+	secondID     sql.NullInt64
+	results      string
+	hasTransfers bool
+}
+
+// sameSubmission reports whether b is a copy of a without money of its own
+func sameSubmission(a, b game) bool {
+	return a.gameDate == b.gameDate &&
+		a.winnerID == b.winnerID &&
+		a.secondID == b.secondID &&
+		a.results == b.results &&
+		!b.hasTransfers
+}
+
+func main() {
+	// Set up logger
+	logger := log.New(os.Stdout, "fixgames: ", log.LstdFlags)
+
+	apply := flag.Bool("apply", false, "clean up the games instead of only listing them")
+	flag.Parse()
+
+	// Load environment variables from .env file
+	envPath := ".env"
+	if err := config.LoadEnv(envPath); err != nil {
+		logger.Printf("Warning: Unable to load .env file: %v", err)
+	}
+
+	// Connect to database
+	database, err := db.Connect()
+	if err != nil {
+		logger.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	games, err := loadGames(database)
+	if err != nil {
+		logger.Fatalf("Failed to load games: %v", err)
+	}
+
+	var sameWinnerAndSecond []int
+	bySeasonHost := make(map[[2]int][]game)
+	var keys [][2]int
+	for _, g := range games {
+		if g.winnerID.Valid && g.winnerID == g.secondID {
+			sameWinnerAndSecond = append(sameWinnerAndSecond, g.id)
+		}
+		key := [2]int{g.seasonID, g.hostID}
+		if len(bySeasonHost[key]) == 0 {
+			keys = append(keys, key)
+		}
+		bySeasonHost[key] = append(bySeasonHost[key], g)
+	}
+
+	var doubles []int
+	conflicts := 0
+	for _, key := range keys {
+		group := bySeasonHost[key]
+		for _, g := range group[1:] {
+			if sameSubmission(group[0], g) {
+				doubles = append(doubles, g.id)
+				fmt.Printf("Game %d is a double submission of game %d\n", g.id, group[0].id)
+				continue
+			}
+			conflicts++
+			fmt.Printf("Game %d has the same season %d and host %d as game %d but differs\n", g.id, key[0], key[1], group[0].id)
+		}
+	}
+	for _, id := range sameWinnerAndSecond {
+		fmt.Printf("Game %d has the same player as winner and second place\n", id)
+	}
+
+	if conflicts > 0 {
+		logger.Fatalf("%d games share a season and host with another game; fix them by hand, nothing was changed", conflicts)
+	}
+	if len(doubles) == 0 && len(sameWinnerAndSecond) == 0 {
+		fmt.Println("Nothing to clean up")
+		return
+	}
+	if !*apply {
+		fmt.Println("Run with -apply to clean up these games")
+		return
+	}
+
+	if err := cleanUp(database, sameWinnerAndSecond, doubles); err != nil {
+		logger.Fatalf("Failed to clean up games: %v", err)
+	}
+	fmt.Printf("Cleared %d second places and deleted %d double submissions\n", len(sameWinnerAndSecond), len(doubles))
+}
+
+// loadGames returns all games ordered by ID, with their finishing order and
+// whether they have ledger entries or settlement payments
+func loadGames(database *sql.DB) ([]game, error) {
+	rows, err := database.Query(`
+		SELECT
+			g.id,
+			g.season_id,
+			g.host_id,
+			DATE_FORMAT(g.game_date, '%Y-%m-%d'),
+			g.winner_id,
+			g.second_place_id,
+			EXISTS (SELECT 1 FROM game_ledger l WHERE l.game_id = g.id)
+				OR EXISTS (SELECT 1 FROM settlement_payments p WHERE p.game_id = g.id)
+		FROM
+			games g
+		ORDER BY
+			g.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []game
+	for rows.Next() {
+		var g game
+		if err := rows.Scan(&g.id, &g.seasonID, &g.hostID, &g.gameDate, &g.winnerID, &g.secondID, &g.hasTransfers); err != nil {
+			return nil, err
+		}
+		games = append(games, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	results, err := loadResults(database)
+	if err != nil {
+		return nil, err
+	}
+	for i := range games {
+		games[i].results = results[games[i].id]
+	}
+	return games, nil
+}
+
+// loadResults returns the finishing order of every game as a comparable
+// string of player IDs by position
+func loadResults(database *sql.DB) (map[int]string, error) {
+	rows, err := database.Query("SELECT game_id, player_id, position FROM game_results")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	places := make(map[int][]string)
+	for rows.Next() {
+		var gameID, playerID, position int
+		if err := rows.Scan(&gameID, &playerID, &position); err != nil {
+			return nil, err
+		}
+		places[gameID] = append(places[gameID], fmt.Sprintf("%d:%d", position, playerID))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make(map[int]string, len(places))
+	for gameID, p := range places {
+		sort.Strings(p)
+		results[gameID] = strings.Join(p, ",")
+	}
+	return results, nil
+}
+
+// cleanUp clears second places equal to the winner and deletes double
+// submissions in one transaction
+func cleanUp(database *sql.DB, sameWinnerAndSecond, doubles []int) error {
+	tx, err := database.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The winner is kept; game_results never held them twice
+	for _, id := range sameWinnerAndSecond {
+		if _, err := tx.Exec("UPDATE games SET second_place_id = NULL WHERE id = ?", id); err != nil {
+			return err
+		}
+	}
+
+	// The results of a copy are deleted with it
+	for _, id := range doubles {
+		if _, err := tx.Exec("DELETE FROM games WHERE id = ?", id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
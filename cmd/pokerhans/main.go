@@ -69,8 +69,11 @@ func main() {
 		http.StripPrefix("/static/", fileServer).ServeHTTP(w, r)
 	}))
 
-	// Routes
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+	// Pages of a group, below /g/{slug}. The handlers run on a copy of h
+	// scoped to the group.
+	groups := http.NewServeMux()
+	groups.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		h := handlers.Scoped(r)
 		logger.Printf("=== ROUTE CALL ===")
 		logger.Printf("PATH: %s", r.URL.Path)
 		logger.Printf("METHOD: %s", r.Method)
@@ -78,11 +81,36 @@ func main() {
 		logger.Printf("USER-AGENT: %s", r.UserAgent())
 
 		if r.URL.Path != "/" {
+			if strings.HasPrefix(r.URL.Path, "/season/") && strings.HasSuffix(r.URL.Path, "/calendar.ics") && r.Method == "GET" {
+				logger.Printf("HANDLER: SeasonCalendarHandler")
+				h.SeasonCalendarHandler(w, r)
+				return
+			}
+			if strings.HasPrefix(r.URL.Path, "/player/") && strings.HasSuffix(r.URL.Path, "/calendar.ics") && r.Method == "GET" {
+				logger.Printf("HANDLER: PlayerCalendarHandler")
+				h.PlayerCalendarHandler(w, r)
+				return
+			}
+			if strings.HasPrefix(r.URL.Path, "/season/") && strings.HasSuffix(r.URL.Path, "/roster") && r.Method == "GET" {
+				logger.Printf("HANDLER: RosterHandler")
+				h.RequireMember(h.RosterHandler)(w, r)
+				return
+			}
 			if strings.HasPrefix(r.URL.Path, "/season/") && r.Method == "GET" {
 				logger.Printf("HANDLER: SeasonHandler")
 				h.SeasonHandler(w, r)
 				return
 			}
+			if strings.HasPrefix(r.URL.Path, "/game/") && strings.HasSuffix(r.URL.Path, "/edit") && r.Method == "GET" {
+				logger.Printf("HANDLER: EditGameHandler")
+				h.RequireMember(h.EditGameHandler)(w, r)
+				return
+			}
+			if strings.HasPrefix(r.URL.Path, "/player/") && r.Method == "GET" {
+				logger.Printf("HANDLER: PlayerHandler")
+				h.PlayerHandler(w, r)
+				return
+			}
 			logger.Printf("HANDLER: NotFound (404)")
 			http.NotFound(w, r)
 			return
@@ -92,37 +120,65 @@ func main() {
 		h.HomeHandler(w, r)
 	})
 
-	http.HandleFunc("/game/add", func(w http.ResponseWriter, r *http.Request) {
-		logger.Printf("=== ROUTE CALL ===")
-		logger.Printf("PATH: /game/add")
-		logger.Printf("METHOD: %s", r.Method)
-		logger.Printf("REMOTE: %s", r.RemoteAddr)
+	route(groups, logger, "/ratings", "GET", "RatingsHandler", scoped((*handlers.Handler).RatingsHandler))
+	route(groups, logger, "/compare", "GET", "CompareHandler", scoped((*handlers.Handler).CompareHandler))
 
-		if r.Method != "POST" {
-			logger.Printf("HANDLER: MethodNotAllowed (405)")
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
+	// Pages and forms that change data are only for members of the group
+	route(groups, logger, "/game/add", "POST", "AddGameHandler", member((*handlers.Handler).AddGameHandler))
+	route(groups, logger, "/game/update-date", "POST", "UpdateGameDateHandler", member((*handlers.Handler).UpdateGameDateHandler))
+	route(groups, logger, "/game/update", "POST", "UpdateGameHandler", member((*handlers.Handler).UpdateGameHandler))
+	route(groups, logger, "/game/delete", "POST", "DeleteGameHandler", member((*handlers.Handler).DeleteGameHandler))
+	route(groups, logger, "/game/plan", "POST", "PlanGameHandler", member((*handlers.Handler).PlanGameHandler))
+	route(groups, logger, "/game/postpone", "POST", "PostponeGameHandler", member((*handlers.Handler).PostponeGameHandler))
+	route(groups, logger, "/game/cancel", "POST", "CancelGameHandler", member((*handlers.Handler).CancelGameHandler))
+	route(groups, logger, "/game/rsvp", "POST", "RSVPHandler", member((*handlers.Handler).RSVPHandler))
+	route(groups, logger, "/season/roster/update", "POST", "UpdateRosterHandler", member((*handlers.Handler).UpdateRosterHandler))
+	route(groups, logger, "/season/roster/copy", "POST", "CopyRosterHandler", member((*handlers.Handler).CopyRosterHandler))
+	route(groups, logger, "/season/schedule", "POST", "GenerateScheduleHandler", member((*handlers.Handler).GenerateScheduleHandler))
+	route(groups, logger, "/settlement/settle", "POST", "SettleTransferHandler", member((*handlers.Handler).SettleTransferHandler))
 
-		logger.Printf("HANDLER: AddGameHandler")
-		h.AddGameHandler(w, r)
-	})
+	// Admin routes of a group
+	route(groups, logger, "/admin/seasons", "GET", "AdminSeasonsHandler", member((*handlers.Handler).AdminSeasonsHandler))
+	route(groups, logger, "/admin/seasons/create", "POST", "CreateSeasonHandler", member((*handlers.Handler).CreateSeasonHandler))
+	route(groups, logger, "/admin/seasons/next", "POST", "StartNextSeasonHandler", member((*handlers.Handler).StartNextSeasonHandler))
+	route(groups, logger, "/admin/seasons/rename", "POST", "RenameSeasonHandler", member((*handlers.Handler).RenameSeasonHandler))
+	route(groups, logger, "/admin/seasons/dates", "POST", "SetSeasonDatesHandler", member((*handlers.Handler).SetSeasonDatesHandler))
+	route(groups, logger, "/admin/seasons/archive", "POST", "ArchiveSeasonHandler", member((*handlers.Handler).ArchiveSeasonHandler))
+	route(groups, logger, "/admin/players", "GET", "AdminPlayersHandler", member((*handlers.Handler).AdminPlayersHandler))
+	route(groups, logger, "/admin/players/create", "POST", "CreatePlayerHandler", member((*handlers.Handler).CreatePlayerHandler))
+	route(groups, logger, "/admin/players/rename", "POST", "RenamePlayerHandler", member((*handlers.Handler).RenamePlayerHandler))
+	route(groups, logger, "/admin/players/active", "POST", "SetPlayerActiveHandler", member((*handlers.Handler).SetPlayerActiveHandler))
+
+	http.Handle("/g/", h.GroupRoutes(groups))
 
-	http.HandleFunc("/game/update-date", func(w http.ResponseWriter, r *http.Request) {
+	// Start page, group list and redirects from URLs without a group
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		logger.Printf("=== ROUTE CALL ===")
-		logger.Printf("PATH: /game/update-date")
+		logger.Printf("PATH: %s", r.URL.Path)
 		logger.Printf("METHOD: %s", r.Method)
 		logger.Printf("REMOTE: %s", r.RemoteAddr)
 
-		if r.Method != "POST" {
-			logger.Printf("HANDLER: MethodNotAllowed (405)")
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		if r.URL.Path != "/" {
+			if r.Method == "GET" {
+				logger.Printf("HANDLER: LegacyRedirectHandler")
+				h.LegacyRedirectHandler(w, r)
+				return
+			}
+			logger.Printf("HANDLER: NotFound (404)")
+			http.NotFound(w, r)
 			return
 		}
 
-		logger.Printf("HANDLER: UpdateGameDateHandler")
-		h.UpdateGameDateHandler(w, r)
+		logger.Printf("HANDLER: GroupsHandler")
+		h.GroupsHandler(w, r)
 	})
+	route(http.DefaultServeMux, logger, "/groups", "GET", "GroupsHandler", h.GroupsHandler)
+	route(http.DefaultServeMux, logger, "/groups/create", "POST", "CreateGroupHandler", h.RequireMember(h.CreateGroupHandler))
+
+	// Login of members
+	route(http.DefaultServeMux, logger, "/login", "GET", "LoginHandler", h.LoginHandler)
+	route(http.DefaultServeMux, logger, "/login/submit", "POST", "SubmitLoginHandler", h.SubmitLoginHandler)
+	route(http.DefaultServeMux, logger, "/logout", "POST", "LogoutHandler", h.LogoutHandler)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -134,19 +190,45 @@ func main() {
 	logger.Printf("=== SERVER STARTING ===")
 	logger.Printf("LISTENING ON: http://localhost:%s", port)
 	logger.Printf("ROUTES:")
-	logger.Printf("  - http://localhost:%s/           -> HomeHandler", port)
-	logger.Printf("  - http://localhost:%s/season/:id -> SeasonHandler", port)
-	logger.Printf("  - http://localhost:%s/game/add   -> AddGameHandler (POST)", port)
-	logger.Printf("  - http://localhost:%s/game/update-date -> UpdateGameDateHandler (POST)", port)
+	logger.Printf("  - http://localhost:%s/           -> GroupsHandler", port)
+	logger.Printf("  - http://localhost:%s/groups     -> GroupsHandler", port)
+	logger.Printf("  - http://localhost:%s/groups/create -> CreateGroupHandler (POST)", port)
+	logger.Printf("  - http://localhost:%s/login      -> LoginHandler", port)
+	logger.Printf("  - http://localhost:%s/login/submit -> SubmitLoginHandler (POST)", port)
+	logger.Printf("  - http://localhost:%s/logout     -> LogoutHandler (POST)", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/   -> HomeHandler", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/season/:id -> SeasonHandler", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/season/:id/roster -> RosterHandler", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/season/:id/calendar.ics -> SeasonCalendarHandler", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/player/:id -> PlayerHandler", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/player/:id/calendar.ics -> PlayerCalendarHandler", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/game/add   -> AddGameHandler (POST)", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/game/update-date -> UpdateGameDateHandler (POST)", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/game/:id/edit -> EditGameHandler", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/game/update -> UpdateGameHandler (POST)", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/game/delete -> DeleteGameHandler (POST)", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/game/plan -> PlanGameHandler (POST)", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/game/postpone -> PostponeGameHandler (POST)", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/game/cancel -> CancelGameHandler (POST)", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/game/rsvp -> RSVPHandler (POST)", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/season/schedule -> GenerateScheduleHandler (POST)", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/settlement/settle -> SettleTransferHandler (POST)", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/ratings -> RatingsHandler", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/compare?a=:id&b=:id -> CompareHandler", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/admin/seasons -> AdminSeasonsHandler", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/admin/seasons/next -> StartNextSeasonHandler (POST)", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/admin/seasons/dates -> SetSeasonDatesHandler (POST)", port)
+	logger.Printf("  - http://localhost:%s/g/:slug/admin/players -> AdminPlayersHandler", port)
 	logger.Printf("  - http://localhost:%s/static/*   -> Static files", port)
 	logger.Printf("Migration Note: Run 'make migrate-up' if you need to apply database migrations")
 	logger.Printf("Tailwind CSS: Run 'make css-watch' in another terminal for CSS hot reloading")
 	logger.Printf("=== SERVER READY ===")
 
-	// Create a custom HTTP server to log when it starts listening
+	// Create a custom HTTP server to log when it starts listening. Every
+	// request that may change data must carry the CSRF token of its form.
 	server := &http.Server{
 		Addr:    addr,
-		Handler: nil, // Use default mux
+		Handler: h.ProtectForms(http.DefaultServeMux),
 	}
 
 	// Log before starting to make it clear we're about to listen
@@ -156,3 +238,42 @@ func main() {
 		logger.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// route registers a handler for a single path that only accepts the given
+// method, logging every call the same way as the other routes
+func route(mux *http.ServeMux, logger *log.Logger, path, method, name string, handler http.HandlerFunc) {
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		logger.Printf("=== ROUTE CALL ===")
+		logger.Printf("PATH: %s", path)
+		logger.Printf("METHOD: %s", r.Method)
+		logger.Printf("REMOTE: %s", r.RemoteAddr)
+
+		if r.Method != method {
+			logger.Printf("HANDLER: MethodNotAllowed (405)")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		logger.Printf("HANDLER: %s", name)
+		handler(w, r)
+	})
+}
+
+// scoped calls a handler method on the handler scoped to the group of the
+// request, see handlers.Handler.GroupRoutes
+func scoped(handler func(*handlers.Handler, http.ResponseWriter, *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handler(handlers.Scoped(r), w, r)
+	}
+}
+
+// member is like scoped but only lets logged-in members of the group
+// through, see handlers.Handler.RequireMember
+func member(handler func(*handlers.Handler, http.ResponseWriter, *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h := handlers.Scoped(r)
+		h.RequireMember(func(w http.ResponseWriter, r *http.Request) {
+			handler(h, w, r)
+		})(w, r)
+	}
+}
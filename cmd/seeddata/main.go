@@ -7,6 +7,7 @@ import (
 
 	"github.com/klausbreyer/pokerhans/internal/config"
 	"github.com/klausbreyer/pokerhans/internal/db"
+	"github.com/klausbreyer/pokerhans/internal/models"
 )
 
 func main() {
@@ -26,17 +27,16 @@ func main() {
 	}
 	defer database.Close()
 
-	// Add a sample season
-	_, err = database.Exec("INSERT INTO seasons (name) VALUES ('Summer 2025')")
+	group, err := models.NewRepository(database).GetGroupBySlug(models.DefaultGroupSlug)
 	if err != nil {
-		logger.Fatalf("Failed to add season: %v", err)
+		logger.Fatalf("Failed to find group %s: %v", models.DefaultGroupSlug, err)
 	}
+	repo := models.NewRepository(database).ForGroup(group.ID)
 
-	// Get the season ID
-	var seasonID int
-	err = database.QueryRow("SELECT id FROM seasons WHERE name = 'Summer 2025'").Scan(&seasonID)
+	// Add a sample season
+	seasonID, err := repo.CreateSeason("Summer 2025", nil, nil)
 	if err != nil {
-		logger.Fatalf("Failed to get season ID: %v", err)
+		logger.Fatalf("Failed to add season: %v", err)
 	}
 
 	// Add some players
@@ -45,23 +45,16 @@ func main() {
 
 	for i, name := range playerNames {
 		// Add player
-		result, err := database.Exec("INSERT INTO players (name) VALUES (?)", name)
+		playerID, err := repo.CreatePlayer(name)
 		if err != nil {
 			logger.Fatalf("Failed to add player %s: %v", name, err)
 		}
+		playerIDs[i] = playerID
+	}
 
-		// Get player ID
-		playerID, err := result.LastInsertId()
-		if err != nil {
-			logger.Fatalf("Failed to get player ID for %s: %v", name, err)
-		}
-		playerIDs[i] = int(playerID)
-
-		// Add player to season
-		_, err = database.Exec("INSERT INTO season_players (season_id, player_id) VALUES (?, ?)", seasonID, playerID)
-		if err != nil {
-			logger.Fatalf("Failed to add player %s to season: %v", name, err)
-		}
+	// Add players to the season roster
+	if err := repo.SetSeasonRoster(seasonID, playerIDs); err != nil {
+		logger.Fatalf("Failed to add players to season: %v", err)
 	}
 
 	fmt.Printf("Successfully added season 'Summer 2025' with %d players\n", len(playerNames))
@@ -4,7 +4,10 @@ go 1.23.0
 
 toolchain go1.24.2
 
-require github.com/go-sql-driver/mysql v1.9.2
+require (
+	github.com/go-sql-driver/mysql v1.9.2
+	golang.org/x/crypto v0.41.0
+)
 
 require (
 	filippo.io/edwards25519 v1.1.0 // indirect
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -27,9 +28,11 @@ func GetDBConfig() DBConfig {
 	}
 }
 
-// DSN returns a formatted MySQL DSN (Data Source Name) string
+// DSN returns a formatted MySQL DSN (Data Source Name) string.
+// clientFoundRows makes RowsAffected report matched rows, so updates that
+// leave a row unchanged are not mistaken for missing rows.
 func (c DBConfig) DSN() string {
-	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true",
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&clientFoundRows=true",
 		c.User, c.Pass, c.Host, c.Port, c.Name)
 }
 
@@ -39,6 +42,65 @@ func (c DBConfig) MigrateDSN() string {
 		c.User, c.Pass, c.Host, c.Port, c.Name)
 }
 
+// PointsConfig contains the points awarded per finishing position
+type PointsConfig struct {
+	Win    int
+	Second int
+}
+
+// GetPointsConfig returns the points configuration from environment variables
+func GetPointsConfig() PointsConfig {
+	return PointsConfig{
+		Win:    getEnvIntWithDefault("POINTS_WIN", 3),
+		Second: getEnvIntWithDefault("POINTS_SECOND", 1),
+	}
+}
+
+// RatingConfig contains the parameters of the skill rating
+type RatingConfig struct {
+	K     int
+	Start int
+}
+
+// GetRatingConfig returns the rating configuration from environment variables
+func GetRatingConfig() RatingConfig {
+	return RatingConfig{
+		K:     getEnvIntWithDefault("RATING_K", 32),
+		Start: getEnvIntWithDefault("RATING_START", 1500),
+	}
+}
+
+// SuggestConfig contains the weights of the next host suggestion
+type SuggestConfig struct {
+	PerWeek    int
+	PerHosting int
+	LastFinal  int
+}
+
+// GetSuggestConfig returns the suggestion weights from environment variables
+func GetSuggestConfig() SuggestConfig {
+	return SuggestConfig{
+		PerWeek:    getEnvIntWithDefault("HOST_WEIGHT_WEEK", 1),
+		PerHosting: getEnvIntWithDefault("HOST_WEIGHT_HOSTED", 4),
+		LastFinal:  getEnvIntWithDefault("HOST_WEIGHT_FINAL", 10),
+	}
+}
+
+// GetSessionSecret returns the secret used to sign cookies, or nil if
+// SESSION_SECRET is not set
+func GetSessionSecret() []byte {
+	secret := os.Getenv("SESSION_SECRET")
+	if secret == "" {
+		return nil
+	}
+	return []byte(secret)
+}
+
+// GetSessionDays returns the number of days a member stays logged in
+func GetSessionDays() int {
+	return getEnvIntWithDefault("SESSION_DAYS", 30)
+}
+
 // getEnvWithDefault returns the value of the environment variable or a default value
 func getEnvWithDefault(key, defaultValue string) string {
 	value := os.Getenv(key)
@@ -48,6 +110,15 @@ func getEnvWithDefault(key, defaultValue string) string {
 	return value
 }
 
+// getEnvIntWithDefault returns the integer value of the environment variable or a default value
+func getEnvIntWithDefault(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
 // LoadEnv loads environment variables from a .env file
 func LoadEnv(filePath string) error {
 	// If file doesn't exist, skip loading
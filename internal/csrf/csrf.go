@@ -0,0 +1,132 @@
+// Package csrf protects forms against cross-site request forgery. Every
+// browser gets a random value in a cookie, and forms carry its HMAC-SHA256
+// signature in a hidden field. Another site can make the browser send the
+// cookie, but cannot read it to put the matching token in the form.
+//
+// The signature also covers the session the browser is logged in with, so a
+// token handed out before logging in is no longer accepted afterwards.
+package csrf
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// Names of the cookie, form field and header used for the token
+const (
+	cookieName = "csrf"
+	FieldName  = "csrf_token"
+	HeaderName = "X-CSRF-Token"
+)
+
+// valueBytes is the number of random bytes in the cookie value
+const valueBytes = 32
+
+// Errors returned by Verify
+var (
+	ErrForeignOrigin = errors.New("request was sent from another site")
+	ErrMissingToken  = errors.New("CSRF token is missing")
+	ErrInvalidToken  = errors.New("CSRF token does not match")
+)
+
+// Protector hands out and checks form tokens
+type Protector struct {
+	secret []byte
+}
+
+// New creates a Protector that signs tokens with the given secret
+func New(secret []byte) *Protector {
+	return &Protector{secret: secret}
+}
+
+// Token returns the token to send with the forms of a page for the given
+// session, which is "" when the browser is not logged in. If the browser has
+// no token cookie yet, a new one is set.
+func (p *Protector) Token(w http.ResponseWriter, r *http.Request, session string) string {
+	if cookie, err := r.Cookie(cookieName); err == nil && cookie.Value != "" {
+		return p.sign(cookie.Value, session)
+	}
+
+	b := make([]byte, valueBytes)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	value := base64.RawURLEncoding.EncodeToString(b)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieName,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+	return p.sign(value, session)
+}
+
+// Verify checks that a request which may change data was sent from a page
+// of this site. GET, HEAD and OPTIONS requests always pass. Others must name
+// this site in their Origin or Referer header and must carry the token of the
+// browser's cookie for the given session, in the form field or the header.
+func (p *Protector) Verify(r *http.Request, session string) error {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+
+	if !sameOrigin(r) {
+		return ErrForeignOrigin
+	}
+
+	cookie, err := r.Cookie(cookieName)
+	if err != nil || cookie.Value == "" {
+		return ErrMissingToken
+	}
+
+	token := r.Header.Get(HeaderName)
+	if token == "" {
+		token = r.PostFormValue(FieldName)
+	}
+	if token == "" {
+		return ErrMissingToken
+	}
+
+	if !hmac.Equal([]byte(token), []byte(p.sign(cookie.Value, session))) {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// sameOrigin reports whether the Origin header of a request, or its Referer
+// if the browser sent no Origin, names this site. Every browser the site
+// supports sends one of them with form submissions, so requests with neither
+// header are rejected rather than trusted on the token alone.
+func sameOrigin(r *http.Request) bool {
+	source := r.Header.Get("Origin")
+	if source == "" {
+		source = r.Referer()
+	}
+	if source == "" {
+		return false
+	}
+
+	// Browsers send "null" for sandboxed and privacy-sensitive origins
+	u, err := url.Parse(source)
+	if err != nil || source == "null" {
+		return false
+	}
+	return u.Host == r.Host
+}
+
+// sign returns the form token belonging to a cookie value and session. The
+// cookie value is base64 and cannot contain the separator.
+func (p *Protector) sign(value, session string) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(value + "|" + session))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,142 @@
+package csrf
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// issue gets a token and the cookie it belongs to from a first page view
+// without a session
+func issue(t *testing.T, p *Protector) (string, *http.Cookie) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	token := p.Token(rec, httptest.NewRequest(http.MethodGet, "http://poker.example/", nil), "")
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected one cookie, got %d", len(cookies))
+	}
+	return token, cookies[0]
+}
+
+// post builds a form submission from a page of this site with the given token
+func post(token string, cookie *http.Cookie) *http.Request {
+	form := url.Values{FieldName: {token}}
+	req := httptest.NewRequest(http.MethodPost, "http://poker.example/g/hans/game/add", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Origin", "http://poker.example")
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	return req
+}
+
+func TestTokenIsStable(t *testing.T) {
+	p := New([]byte("secret"))
+	token, cookie := issue(t, p)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://poker.example/", nil)
+	req.AddCookie(cookie)
+	if got := p.Token(rec, req, ""); got != token {
+		t.Errorf("Expected the same token for the same cookie, got %q and %q", token, got)
+	}
+	if len(rec.Result().Cookies()) != 0 {
+		t.Errorf("Expected no new cookie when one exists")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	p := New([]byte("secret"))
+	token, cookie := issue(t, p)
+
+	if err := p.Verify(post(token, cookie), ""); err != nil {
+		t.Errorf("Expected a matching token to pass, got %v", err)
+	}
+
+	req := post("", cookie)
+	req.Header.Set(HeaderName, token)
+	if err := p.Verify(req, ""); err != nil {
+		t.Errorf("Expected a matching token in the header to pass, got %v", err)
+	}
+
+	if err := p.Verify(post("", cookie), ""); !errors.Is(err, ErrMissingToken) {
+		t.Errorf("Expected ErrMissingToken without token, got %v", err)
+	}
+	if err := p.Verify(post(token, nil), ""); !errors.Is(err, ErrMissingToken) {
+		t.Errorf("Expected ErrMissingToken without cookie, got %v", err)
+	}
+	if err := p.Verify(post("x"+token, cookie), ""); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for a wrong token, got %v", err)
+	}
+
+	forged, _ := issue(t, New([]byte("other")))
+	if err := p.Verify(post(forged, cookie), ""); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for a token signed with another secret, got %v", err)
+	}
+
+	get := httptest.NewRequest(http.MethodGet, "http://poker.example/", nil)
+	if err := p.Verify(get, ""); err != nil {
+		t.Errorf("Expected GET to pass without token, got %v", err)
+	}
+}
+
+func TestVerifyOrigin(t *testing.T) {
+	p := New([]byte("secret"))
+	token, cookie := issue(t, p)
+
+	tests := []struct {
+		origin   string
+		referer  string
+		expected error
+	}{
+		{"", "", ErrForeignOrigin},
+		{"http://poker.example", "", nil},
+		{"", "http://poker.example/g/hans/season/1", nil},
+		{"https://evil.example", "", ErrForeignOrigin},
+		{"", "https://evil.example/form", ErrForeignOrigin},
+		{"https://evil.example", "http://poker.example/", ErrForeignOrigin},
+		{"null", "", ErrForeignOrigin},
+	}
+
+	for _, tt := range tests {
+		req := post(token, cookie)
+		req.Header.Del("Origin")
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		if tt.referer != "" {
+			req.Header.Set("Referer", tt.referer)
+		}
+		if err := p.Verify(req, ""); !errors.Is(err, tt.expected) {
+			t.Errorf("Expected %v for origin %q and referer %q, got %v", tt.expected, tt.origin, tt.referer, err)
+		}
+	}
+}
+
+func TestTokenIsBoundToSession(t *testing.T) {
+	p := New([]byte("secret"))
+	loggedOut, cookie := issue(t, p)
+
+	req := httptest.NewRequest(http.MethodGet, "http://poker.example/", nil)
+	req.AddCookie(cookie)
+	loggedIn := p.Token(httptest.NewRecorder(), req, "session-a")
+	if loggedIn == loggedOut {
+		t.Errorf("Expected a different token after logging in")
+	}
+
+	if err := p.Verify(post(loggedIn, cookie), "session-a"); err != nil {
+		t.Errorf("Expected the token of the session to pass, got %v", err)
+	}
+	if err := p.Verify(post(loggedOut, cookie), "session-a"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for a token from before the login, got %v", err)
+	}
+	if err := p.Verify(post(loggedIn, cookie), "session-b"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for a token of another session, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+// Package flash passes one-time messages across a redirect. The message is
+// stored in a cookie signed with HMAC-SHA256, so it cannot be forged, and is
+// removed as soon as it has been read.
+package flash
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// cookieName is the name of the cookie holding the message
+const cookieName = "flash"
+
+// Level is the severity of a message
+type Level string
+
+// Message levels
+const (
+	LevelSuccess Level = "success"
+	LevelWarning Level = "warning"
+	LevelError   Level = "error"
+)
+
+// Message is a message shown once on the next page
+type Message struct {
+	Level Level  `json:"level"`
+	Text  string `json:"text"`
+}
+
+// Store reads and writes signed flash cookies
+type Store struct {
+	secret []byte
+}
+
+// New creates a Store that signs cookies with the given secret
+func New(secret []byte) *Store {
+	return &Store{secret: secret}
+}
+
+// Set stores a message to be shown on the next page
+func (s *Store) Set(w http.ResponseWriter, level Level, text string) {
+	payload, err := json.Marshal(Message{Level: level, Text: text})
+	if err != nil {
+		return
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieName,
+		Value:    encoded + "." + s.sign(encoded),
+		Path:     "/",
+		MaxAge:   60,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// Pop returns the message of the request, if any, and removes the cookie.
+// Messages with a missing or wrong signature are dropped.
+func (s *Store) Pop(w http.ResponseWriter, r *http.Request) *Message {
+	cookie, err := r.Cookie(cookieName)
+	if err != nil {
+		return nil
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieName,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	encoded, signature, ok := strings.Cut(cookie.Value, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.sign(encoded))) {
+		return nil
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil
+	}
+
+	var m Message
+	if err := json.Unmarshal(payload, &m); err != nil {
+		return nil
+	}
+	return &m
+}
+
+// sign returns the signature of a cookie value
+func (s *Store) sign(value string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,81 @@
+package flash
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// roundTrip sets a message with one store and reads it back with another
+func roundTrip(t *testing.T, writer, reader *Store, mutate func(*http.Cookie)) *Message {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	writer.Set(rec, LevelSuccess, "Game saved")
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected one cookie, got %d", len(cookies))
+	}
+	if mutate != nil {
+		mutate(cookies[0])
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookies[0])
+	return reader.Pop(httptest.NewRecorder(), req)
+}
+
+func TestRoundTrip(t *testing.T) {
+	store := New([]byte("secret"))
+
+	m := roundTrip(t, store, store, nil)
+	if m == nil {
+		t.Fatalf("Expected a message")
+	}
+	if m.Level != LevelSuccess || m.Text != "Game saved" {
+		t.Errorf("Expected success message %q, got %+v", "Game saved", m)
+	}
+}
+
+func TestRejectsForgedMessages(t *testing.T) {
+	store := New([]byte("secret"))
+
+	if m := roundTrip(t, store, New([]byte("other")), nil); m != nil {
+		t.Errorf("Expected message signed with another secret to be dropped, got %+v", m)
+	}
+
+	tamper := func(c *http.Cookie) { c.Value = "x" + c.Value }
+	if m := roundTrip(t, store, store, tamper); m != nil {
+		t.Errorf("Expected tampered message to be dropped, got %+v", m)
+	}
+}
+
+func TestPopClearsCookie(t *testing.T) {
+	store := New([]byte("secret"))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: cookieName, Value: "garbage"})
+	rec := httptest.NewRecorder()
+
+	if m := store.Pop(rec, req); m != nil {
+		t.Errorf("Expected no message, got %+v", m)
+	}
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].MaxAge >= 0 {
+		t.Errorf("Expected the flash cookie to be removed, got %v", cookies)
+	}
+}
+
+func TestPopWithoutCookie(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+
+	if m := New([]byte("secret")).Pop(rec, req); m != nil {
+		t.Errorf("Expected no message, got %+v", m)
+	}
+	if len(rec.Result().Cookies()) != 0 {
+		t.Errorf("Expected no cookie to be set")
+	}
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/klausbreyer/pokerhans/internal/flash"
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+// AdminPlayersHandler lists all players with forms to add, rename and deactivate them
+func (h *Handler) AdminPlayersHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: AdminPlayersHandler - Listing players")
+
+	if !h.authorizeManage(w, r) {
+		return
+	}
+
+	players, err := h.Repo.GetAllPlayers()
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting players failed: %v", err)
+		http.Error(w, "Failed to load players", http.StatusInternalServerError)
+		return
+	}
+	h.Logger.Printf("DATA: Found %d players", len(players))
+
+	data := struct {
+		Page
+		Players []models.Player
+	}{
+		Page:    h.newPage(w, r),
+		Players: players,
+	}
+
+	h.Logger.Printf("RENDER: Rendering layout template with player admin content")
+	h.render(w, "admin_players", data)
+}
+
+// CreatePlayerHandler handles adding a new player
+func (h *Handler) CreatePlayerHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: CreatePlayerHandler - Processing player creation")
+
+	if !h.authorizeManage(w, r) {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		h.Logger.Printf("ERROR: Empty player name")
+		http.Error(w, "Player name is required", http.StatusBadRequest)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Name = %s", name)
+
+	playerID, err := h.Repo.CreatePlayer(name)
+	if err != nil {
+		h.Logger.Printf("ERROR: Creating player in database: %v", err)
+		http.Error(w, "Failed to create player", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Player %d created successfully", playerID)
+	h.Flash.Set(w, flash.LevelSuccess, "Added player "+name)
+
+	redirectURL := h.path("/admin/players")
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// RenamePlayerHandler handles renaming a player
+func (h *Handler) RenamePlayerHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: RenamePlayerHandler - Processing player rename")
+
+	if !h.authorizeManage(w, r) {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	playerID, err := strconv.Atoi(r.FormValue("player_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid player_id: %s", r.FormValue("player_id"))
+		http.Error(w, "Invalid player ID", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		h.Logger.Printf("ERROR: Empty player name")
+		http.Error(w, "Player name is required", http.StatusBadRequest)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Player ID = %d, Name = %s", playerID, name)
+
+	err = h.Repo.RenamePlayer(playerID, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: Player %d not found", playerID)
+		http.Error(w, "Player not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Renaming player in database: %v", err)
+		http.Error(w, "Failed to rename player", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Player renamed successfully")
+	h.Flash.Set(w, flash.LevelSuccess, "Renamed player to "+name)
+
+	redirectURL := h.path("/admin/players")
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// SetPlayerActiveHandler handles activating and deactivating a player
+func (h *Handler) SetPlayerActiveHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: SetPlayerActiveHandler - Processing player status change")
+
+	if !h.authorizeManage(w, r) {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	playerID, err := strconv.Atoi(r.FormValue("player_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid player_id: %s", r.FormValue("player_id"))
+		http.Error(w, "Invalid player ID", http.StatusBadRequest)
+		return
+	}
+
+	active, err := strconv.ParseBool(r.FormValue("active"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid active flag: %s", r.FormValue("active"))
+		http.Error(w, "Invalid active flag", http.StatusBadRequest)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Player ID = %d, Active = %t", playerID, active)
+
+	err = h.Repo.SetPlayerActive(playerID, active)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: Player %d not found", playerID)
+		http.Error(w, "Player not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Updating player status in database: %v", err)
+		http.Error(w, "Failed to update player", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Player status updated successfully")
+	if active {
+		h.Flash.Set(w, flash.LevelSuccess, "Player is active again")
+	} else {
+		h.Flash.Set(w, flash.LevelSuccess, "Player is now inactive")
+	}
+
+	redirectURL := h.path("/admin/players")
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klausbreyer/pokerhans/internal/flash"
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+// AdminSeasonsHandler lists all seasons with forms to create, rename and archive them
+func (h *Handler) AdminSeasonsHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: AdminSeasonsHandler - Listing seasons")
+
+	if !h.authorizeManage(w, r) {
+		return
+	}
+
+	seasons, err := h.Repo.GetSeasons()
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting seasons failed: %v", err)
+		http.Error(w, "Failed to load seasons", http.StatusInternalServerError)
+		return
+	}
+	h.Logger.Printf("DATA: Found %d seasons", len(seasons))
+
+	// Start next season closes the current season, which is empty if all
+	// seasons are archived
+	current, _ := models.CurrentSeason(seasons, time.Now())
+
+	data := struct {
+		Page
+		Seasons        []models.Season
+		Current        models.Season
+		NextSeasonName string
+	}{
+		Page:           h.newPage(w, r),
+		Seasons:        seasons,
+		Current:        current,
+		NextSeasonName: models.NextSeasonName(current.Name, time.Now()),
+	}
+
+	h.Logger.Printf("RENDER: Rendering layout template with season admin content")
+	h.render(w, "admin_seasons", data)
+}
+
+// CreateSeasonHandler handles creating a new season
+func (h *Handler) CreateSeasonHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: CreateSeasonHandler - Processing season creation")
+
+	if !h.authorizeManage(w, r) {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		h.Logger.Printf("ERROR: Empty season name")
+		http.Error(w, "Season name is required", http.StatusBadRequest)
+		return
+	}
+
+	startDate, endDate, err := parseSeasonDates(r)
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid season dates: %v", err)
+		http.Error(w, "Invalid date format", http.StatusBadRequest)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Name = %s, Start = %s, End = %s", name, r.FormValue("start_date"), r.FormValue("end_date"))
+
+	seasonID, err := h.Repo.CreateSeason(name, startDate, endDate)
+	if errors.Is(err, models.ErrInvalidSeasonDates) {
+		h.Logger.Printf("ERROR: Season ends before it starts")
+		h.Flash.Set(w, flash.LevelError, "A season cannot end before it starts")
+		http.Redirect(w, r, h.path("/admin/seasons"), http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Creating season in database: %v", err)
+		http.Error(w, "Failed to create season", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Season %d created successfully", seasonID)
+	h.Flash.Set(w, flash.LevelSuccess, "Created season "+name)
+
+	// Redirect to the new season
+	redirectURL := h.path("/season/" + strconv.Itoa(seasonID))
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// StartNextSeasonHandler handles closing the current season and starting the
+// next one with its roster
+func (h *Handler) StartNextSeasonHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: StartNextSeasonHandler - Processing start of next season")
+
+	if !h.authorizeManage(w, r) {
+		return
+	}
+
+	season, err := h.Repo.StartNextSeason()
+	if errors.Is(err, models.ErrUpcomingGames) {
+		h.Logger.Printf("ERROR: Current season still has upcoming games")
+		h.Flash.Set(w, flash.LevelError, "The current season still has upcoming games. Record or cancel them first.")
+		http.Redirect(w, r, h.path("/admin/seasons"), http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Starting next season in database: %v", err)
+		http.Error(w, "Failed to start next season", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Season %d (%s) started", season.ID, season.Name)
+	h.Flash.Set(w, flash.LevelSuccess, "Started "+season.Name+" with the previous roster")
+
+	redirectURL := h.path("/season/" + strconv.Itoa(season.ID))
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// RenameSeasonHandler handles renaming a season
+func (h *Handler) RenameSeasonHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: RenameSeasonHandler - Processing season rename")
+
+	if !h.authorizeManage(w, r) {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	seasonID, err := strconv.Atoi(r.FormValue("season_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid season_id: %s", r.FormValue("season_id"))
+		http.Error(w, "Invalid season ID", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		h.Logger.Printf("ERROR: Empty season name")
+		http.Error(w, "Season name is required", http.StatusBadRequest)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Season ID = %d, Name = %s", seasonID, name)
+
+	err = h.Repo.RenameSeason(seasonID, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: Season %d not found", seasonID)
+		http.Error(w, "Season not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Renaming season in database: %v", err)
+		http.Error(w, "Failed to rename season", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Season renamed successfully")
+	h.Flash.Set(w, flash.LevelSuccess, "Renamed season to "+name)
+
+	redirectURL := h.path("/admin/seasons")
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// SetSeasonDatesHandler handles changing the date range of a season
+func (h *Handler) SetSeasonDatesHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: SetSeasonDatesHandler - Processing season dates")
+
+	if !h.authorizeManage(w, r) {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	seasonID, err := strconv.Atoi(r.FormValue("season_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid season_id: %s", r.FormValue("season_id"))
+		http.Error(w, "Invalid season ID", http.StatusBadRequest)
+		return
+	}
+
+	startDate, endDate, err := parseSeasonDates(r)
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid season dates: %v", err)
+		http.Error(w, "Invalid date format", http.StatusBadRequest)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Season ID = %d, Start = %s, End = %s", seasonID, r.FormValue("start_date"), r.FormValue("end_date"))
+
+	err = h.Repo.SetSeasonDates(seasonID, startDate, endDate)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		h.Logger.Printf("ERROR: Season %d not found", seasonID)
+		http.Error(w, "Season not found", http.StatusNotFound)
+		return
+	case errors.Is(err, models.ErrInvalidSeasonDates):
+		h.Logger.Printf("ERROR: Season ends before it starts")
+		h.Flash.Set(w, flash.LevelError, "A season cannot end before it starts")
+		http.Redirect(w, r, h.path("/admin/seasons"), http.StatusSeeOther)
+		return
+	case errors.Is(err, models.ErrDateOutsideSeason):
+		h.Logger.Printf("ERROR: Games of season %d fall outside the new dates", seasonID)
+		h.Flash.Set(w, flash.LevelError, "Some games of the season fall outside these dates")
+		http.Redirect(w, r, h.path("/admin/seasons"), http.StatusSeeOther)
+		return
+	case err != nil:
+		h.Logger.Printf("ERROR: Setting season dates in database: %v", err)
+		http.Error(w, "Failed to set season dates", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Season dates set successfully")
+	h.Flash.Set(w, flash.LevelSuccess, "Season dates saved")
+
+	redirectURL := h.path("/admin/seasons")
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// ArchiveSeasonHandler handles archiving a season
+func (h *Handler) ArchiveSeasonHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: ArchiveSeasonHandler - Processing season archive")
+
+	if !h.authorizeManage(w, r) {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	seasonID, err := strconv.Atoi(r.FormValue("season_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid season_id: %s", r.FormValue("season_id"))
+		http.Error(w, "Invalid season ID", http.StatusBadRequest)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Season ID = %d", seasonID)
+
+	err = h.Repo.ArchiveSeason(seasonID)
+	if errors.Is(err, models.ErrUpcomingGames) {
+		h.Logger.Printf("ERROR: Season %d still has upcoming games", seasonID)
+		h.Flash.Set(w, flash.LevelError, "The season still has upcoming games. Record or cancel them first.")
+		http.Redirect(w, r, h.path("/admin/seasons"), http.StatusSeeOther)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: Season %d not found", seasonID)
+		http.Error(w, "Season not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Archiving season in database: %v", err)
+		http.Error(w, "Failed to archive season", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Season archived successfully")
+	h.Flash.Set(w, flash.LevelSuccess, "Season archived")
+
+	redirectURL := h.path("/admin/seasons")
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// parseSeasonDates reads the optional start_date and end_date fields of a
+// season form
+func parseSeasonDates(r *http.Request) (startDate, endDate *time.Time, err error) {
+	if startDate, err = optionalDate(r.FormValue("start_date")); err != nil {
+		return nil, nil, err
+	}
+	if endDate, err = optionalDate(r.FormValue("end_date")); err != nil {
+		return nil, nil, err
+	}
+	return startDate, endDate, nil
+}
+
+// optionalDate parses a date in the format 2006-01-02, or returns nil if the
+// value is empty
+func optionalDate(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	d, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/klausbreyer/pokerhans/internal/flash"
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+// sessionCookieName is the name of the cookie holding the session token
+const sessionCookieName = "session"
+
+// memberKey is the context key of the member logged in for a request
+type memberKey struct{}
+
+// roleKey is the context key of the member's role in the group of a request
+type roleKey struct{}
+
+// LoginHandler handles the login page
+func (h *Handler) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: LoginHandler - Rendering login form")
+
+	data := struct {
+		Page
+		Next string
+	}{
+		Page: h.newPage(w, r),
+		Next: localPath(r.URL.Query().Get("next")),
+	}
+
+	h.Logger.Printf("RENDER: Rendering layout template with login content")
+	h.render(w, "login", data)
+}
+
+// SubmitLoginHandler handles logging in with email and password
+func (h *Handler) SubmitLoginHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: SubmitLoginHandler - Processing login")
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	email := r.FormValue("email")
+	next := localPath(r.FormValue("next"))
+	h.Logger.Printf("PARAM: Email = %s, Next = %s", models.NormalizeEmail(email), next)
+
+	member, err := h.Repo.Authenticate(email, r.FormValue("password"))
+	if errors.Is(err, models.ErrInvalidLogin) {
+		h.Logger.Printf("ERROR: Login failed for %s", models.NormalizeEmail(email))
+		h.Flash.Set(w, flash.LevelError, "Wrong email or password")
+		http.Redirect(w, r, "/login?next="+url.QueryEscape(next), http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Checking login: %v", err)
+		http.Error(w, "Failed to log in", http.StatusInternalServerError)
+		return
+	}
+
+	token, err := h.Repo.CreateSession(member.ID, h.SessionTTL)
+	if err != nil {
+		h.Logger.Printf("ERROR: Creating session: %v", err)
+		http.Error(w, "Failed to log in", http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(h.SessionTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	h.Logger.Printf("SUCCESS: Member %d logged in", member.ID)
+	h.Flash.Set(w, flash.LevelSuccess, "Logged in as "+member.Name)
+
+	h.Logger.Printf("REDIRECT: To %s", next)
+	http.Redirect(w, r, next, http.StatusSeeOther)
+}
+
+// LogoutHandler handles logging out. It ends the session in the database,
+// so the cookie cannot be used again.
+func (h *Handler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: LogoutHandler - Processing logout")
+
+	if cookie, err := r.Cookie(sessionCookieName); err == nil {
+		if err := h.Repo.DeleteSession(cookie.Value); err != nil {
+			h.Logger.Printf("ERROR: Deleting session: %v", err)
+			http.Error(w, "Failed to log out", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	h.Logger.Printf("SUCCESS: Logged out")
+	h.Flash.Set(w, flash.LevelSuccess, "Logged out")
+
+	redirectURL := h.returnPath(r)
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// RequireMember only lets requests of logged-in members through to next.
+// Within a group, the member must also belong to it; their role is then
+// available to the authorize checks of the handlers. Others are sent to the
+// login page and come back to where they were afterwards.
+func (h *Handler) RequireMember(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		member := h.currentMember(r)
+		if member == nil {
+			h.Logger.Printf("AUTH: Not logged in")
+			h.Flash.Set(w, flash.LevelError, "Please log in to make changes")
+			redirectURL := "/login?next=" + url.QueryEscape(h.returnPath(r))
+			h.Logger.Printf("REDIRECT: To %s", redirectURL)
+			http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), memberKey{}, member)
+		if h.Group.ID != 0 {
+			role, err := h.Repo.GetGroupRole(member.ID)
+			if errors.Is(err, sql.ErrNoRows) {
+				h.Logger.Printf("AUTH: Member %d is not in group %s", member.ID, h.Group.Slug)
+				h.forbidden(w, r, "You are not a member of "+h.Group.Name+".")
+				return
+			}
+			if err != nil {
+				h.Logger.Printf("ERROR: Getting group role: %v", err)
+				http.Error(w, "Failed to check membership", http.StatusInternalServerError)
+				return
+			}
+			h.Logger.Printf("AUTH: Member %d is %s", member.ID, role)
+			ctx = context.WithValue(ctx, roleKey{}, role)
+		}
+
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// ProtectForms rejects requests that may change data unless they come from
+// a form of this site with a valid CSRF token, see package csrf
+func (h *Handler) ProtectForms(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := h.CSRF.Verify(r, csrfSession(r)); err != nil {
+			h.Logger.Printf("CSRF: Rejected %s %s: %v", r.Method, r.URL.Path, err)
+			h.forbidden(w, r, "The form has expired or was sent from another site. Please reload the page and try again.")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// csrfSession returns what form tokens are bound to: the hash of the
+// session cookie, or "" if the browser has none. Logging in or out therefore
+// invalidates the tokens of pages loaded before.
+func csrfSession(r *http.Request) string {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil || cookie.Value == "" {
+		return ""
+	}
+	return models.HashSessionToken(cookie.Value)
+}
+
+// authorizeManage checks that the member may manage the group. Otherwise
+// it renders the 403 page and returns false.
+func (h *Handler) authorizeManage(w http.ResponseWriter, r *http.Request) bool {
+	if role := currentRole(r); !role.CanManage() {
+		h.Logger.Printf("AUTH: Role %q may not manage the group", role)
+		h.forbidden(w, r, "Only admins can manage seasons, players and rosters.")
+		return false
+	}
+	return true
+}
+
+// authorizeSeason checks that the member may record and change games of a
+// season. Otherwise it renders the 403 page and returns false.
+func (h *Handler) authorizeSeason(w http.ResponseWriter, r *http.Request, seasonID int) bool {
+	role := currentRole(r)
+	if role.CanManage() {
+		return true
+	}
+
+	currentSeasonID := 0
+	current, err := h.Repo.GetCurrentSeason()
+	if err == nil {
+		currentSeasonID = current.ID
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: Getting current season failed: %v", err)
+		http.Error(w, "Failed to load seasons", http.StatusInternalServerError)
+		return false
+	}
+
+	if !role.CanChangeSeason(seasonID, currentSeasonID) {
+		h.Logger.Printf("AUTH: Role %q may not change season %d (current %d)", role, seasonID, currentSeasonID)
+		message := "Members can only record games of the current season."
+		if role != models.RoleMember {
+			message = "Viewers cannot make changes."
+		}
+		h.forbidden(w, r, message)
+		return false
+	}
+	return true
+}
+
+// authorizeGame loads a game and checks that the member may change it, see
+// authorizeSeason. It writes an error response and returns false if the game
+// cannot be loaded or may not be changed.
+func (h *Handler) authorizeGame(w http.ResponseWriter, r *http.Request, gameID int) (models.Game, bool) {
+	game, _, ok := h.loadGame(w, gameID)
+	if !ok {
+		return models.Game{}, false
+	}
+	return game, h.authorizeSeason(w, r, game.SeasonID)
+}
+
+// forbidden renders the 403 page with a message saying what is not allowed
+func (h *Handler) forbidden(w http.ResponseWriter, r *http.Request, message string) {
+	data := struct {
+		Page
+		Message string
+	}{
+		Page:    h.newPage(w, r),
+		Message: message,
+	}
+
+	h.Logger.Printf("RENDER: Rendering layout template with forbidden content")
+	h.renderStatus(w, http.StatusForbidden, "forbidden", data)
+}
+
+// currentRole returns the role of the member in the group of the request,
+// as found by RequireMember. Without one, the request may change nothing.
+func currentRole(r *http.Request) models.Role {
+	role, _ := r.Context().Value(roleKey{}).(models.Role)
+	return role
+}
+
+// currentMember returns the member logged in for a request, or nil
+func (h *Handler) currentMember(r *http.Request) *models.Member {
+	if member, ok := r.Context().Value(memberKey{}).(*models.Member); ok {
+		return member
+	}
+
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return nil
+	}
+
+	member, err := h.Repo.GetSessionMember(cookie.Value)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			h.Logger.Printf("ERROR: Getting session member: %v", err)
+		}
+		return nil
+	}
+	return &member
+}
+
+// returnPath returns the page to come back to after logging in or out: the
+// requested page for GET requests, otherwise the page the form was sent from
+func (h *Handler) returnPath(r *http.Request) string {
+	if r.Method == http.MethodGet {
+		return h.path(r.URL.RequestURI())
+	}
+
+	if referer, err := url.Parse(r.Referer()); err == nil && referer.Host == r.Host {
+		return localPath(referer.RequestURI())
+	}
+	return h.path("/")
+}
+
+// localPath returns next if it is a path on this site, and "/" otherwise, so
+// that redirects after login cannot lead to other sites
+func localPath(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
+
+// isHTTPS reports whether the request reached the site over HTTPS, either
+// directly or through a proxy such as the one of Fly.io
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
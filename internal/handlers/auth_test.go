@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+func TestLocalPath(t *testing.T) {
+	tests := map[string]string{
+		"/g/hans/season/3":        "/g/hans/season/3",
+		"/g/hans/compare?a=1&b=2": "/g/hans/compare?a=1&b=2",
+		"":                        "/",
+		"g/hans":                  "/",
+		"//evil.example":          "/",
+		"/\\evil.example":         "/",
+		"https://evil.example/":   "/",
+	}
+
+	for next, expected := range tests {
+		if got := localPath(next); got != expected {
+			t.Errorf("Expected localPath(%q) to be %q, got %q", next, expected, got)
+		}
+	}
+}
+
+func TestCSRFSession(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if got := csrfSession(req); got != "" {
+		t.Errorf("Expected no session without cookie, got %q", got)
+	}
+
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: "token"})
+	if got := csrfSession(req); got != models.HashSessionToken("token") {
+		t.Errorf("Expected the hash of the session token, got %q", got)
+	}
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klausbreyer/pokerhans/internal/ical"
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+// SeasonCalendarHandler serves the games of a season as an iCalendar feed
+func (h *Handler) SeasonCalendarHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: SeasonCalendarHandler - Processing season calendar")
+
+	seasonID, ok := calendarID(r, `^/season/(\d+)/calendar\.ics$`)
+	if !ok {
+		h.Logger.Printf("ERROR: Invalid calendar URL: %s", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Season ID = %d", seasonID)
+
+	season, err := h.Repo.GetSeason(seasonID)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: Season %d not found", seasonID)
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting season failed: %v", err)
+		http.Error(w, "Failed to load season", http.StatusInternalServerError)
+		return
+	}
+
+	games, err := h.Repo.GetGames(seasonID)
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting games failed: %v", err)
+		http.Error(w, "Failed to load games", http.StatusInternalServerError)
+		return
+	}
+	h.Logger.Printf("DATA: Found %d games for season %d", len(games), seasonID)
+
+	cal := ical.Calendar{Name: h.Group.Name + " – " + season.Name}
+	for _, g := range games {
+		cal.Events = append(cal.Events, gameEvent(g, season.Name))
+	}
+
+	h.writeCalendar(w, cal, fmt.Sprintf("season-%d.ics", seasonID))
+}
+
+// PlayerCalendarHandler serves the games of a player as an iCalendar feed
+func (h *Handler) PlayerCalendarHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: PlayerCalendarHandler - Processing player calendar")
+
+	playerID, ok := calendarID(r, `^/player/(\d+)/calendar\.ics$`)
+	if !ok {
+		h.Logger.Printf("ERROR: Invalid calendar URL: %s", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Player ID = %d", playerID)
+
+	player, err := h.Repo.GetPlayer(playerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: Player %d not found", playerID)
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting player failed: %v", err)
+		http.Error(w, "Failed to load player", http.StatusInternalServerError)
+		return
+	}
+
+	games, err := h.Repo.GetPlayerGames(playerID)
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting games failed: %v", err)
+		http.Error(w, "Failed to load games", http.StatusInternalServerError)
+		return
+	}
+	h.Logger.Printf("DATA: Found %d games for player %d", len(games), playerID)
+
+	// The events name the season they belong to
+	seasons, err := h.Repo.GetSeasons()
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting seasons failed: %v", err)
+		http.Error(w, "Failed to load seasons", http.StatusInternalServerError)
+		return
+	}
+	seasonNames := make(map[int]string, len(seasons))
+	for _, s := range seasons {
+		seasonNames[s.ID] = s.Name
+	}
+
+	cal := ical.Calendar{Name: h.Group.Name + " – " + player.Name}
+	for _, g := range games {
+		cal.Events = append(cal.Events, gameEvent(g, seasonNames[g.SeasonID]))
+	}
+
+	h.writeCalendar(w, cal, fmt.Sprintf("player-%d.ics", playerID))
+}
+
+// writeCalendar sends a calendar as an .ics file
+func (h *Handler) writeCalendar(w http.ResponseWriter, cal ical.Calendar, filename string) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="`+filename+`"`)
+
+	if err := cal.Encode(w, time.Now()); err != nil {
+		h.Logger.Printf("ERROR: Writing calendar failed: %v", err)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Calendar %s with %d events sent", filename, len(cal.Events))
+}
+
+// calendarID extracts the ID from a calendar URL matching pattern
+func calendarID(r *http.Request, pattern string) (int, bool) {
+	matches := regexp.MustCompile(pattern).FindStringSubmatch(r.URL.Path)
+	if len(matches) < 2 {
+		return 0, false
+	}
+	id, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// gameEvent turns a game into a calendar event. The UID only depends on the
+// game ID, so calendars move the event when the date changes instead of
+// adding a second one; the sequence is the game's revision, which grows with
+// every change.
+func gameEvent(g models.Game, seasonName string) ical.Event {
+	event := ical.Event{
+		UID:      fmt.Sprintf("game-%d@pokerhans", g.ID),
+		Sequence: g.Revision,
+		Date:     g.GameDate,
+		Summary:  "Poker at " + g.HostName + "'s",
+		Status:   ical.StatusConfirmed,
+	}
+
+	var lines []string
+	if seasonName != "" {
+		lines = append(lines, "Season: "+seasonName)
+	}
+
+	switch {
+	case g.Status == models.GameStatusCancelled:
+		event.Status = ical.StatusCancelled
+		lines = append(lines, "Cancelled")
+
+	case g.IsUpcoming():
+		if g.Status == models.GameStatusPostponed {
+			lines = append(lines, "Postponed")
+		}
+		for _, status := range []string{models.AttendanceYes, models.AttendanceMaybe} {
+			if names := attendeeNames(g.AttendeesWith(status)); names != "" {
+				lines = append(lines, strings.ToUpper(status[:1])+status[1:]+": "+names)
+			}
+		}
+
+	case g.IsPlayed():
+		if len(g.Results) > 0 {
+			lines = append(lines, "", "Results:")
+			for _, res := range g.Results {
+				lines = append(lines, res.Placement()+" "+res.PlayerName)
+			}
+		}
+		if len(g.Ledger) > 0 {
+			lines = append(lines, "", "Pot: "+g.Pot().String())
+			for _, b := range g.Balances() {
+				lines = append(lines, b.Name+": "+b.Net.String())
+			}
+		}
+	}
+
+	event.Description = strings.Join(lines, "\n")
+	return event
+}
+
+// attendeeNames joins the player names of attendance entries
+func attendeeNames(attendees []models.Attendance) string {
+	names := make([]string, len(attendees))
+	for i, a := range attendees {
+		names[i] = a.PlayerName
+	}
+	return strings.Join(names, ", ")
+}
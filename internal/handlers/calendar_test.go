@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klausbreyer/pokerhans/internal/ical"
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+func TestGameEvent(t *testing.T) {
+	date := time.Date(2025, time.March, 7, 0, 0, 0, 0, time.UTC)
+
+	played := models.Game{
+		ID:       12,
+		HostName: "Anna",
+		GameDate: date,
+		Status:   models.GameStatusPlayed,
+		Revision: 1,
+		Results:  []models.GameResult{{PlayerName: "Max", Position: 1}, {PlayerName: "Lisa", Position: 2}},
+	}
+	e := gameEvent(played, "Spring 2025")
+	if e.UID != "game-12@pokerhans" {
+		t.Errorf("Expected UID %q, got %q", "game-12@pokerhans", e.UID)
+	}
+	if e.Sequence != 1 || e.Status != ical.StatusConfirmed {
+		t.Errorf("Expected sequence 1 and a confirmed event, got %d and %s", e.Sequence, e.Status)
+	}
+	if e.Summary != "Poker at Anna's" {
+		t.Errorf("Expected the host in the summary, got %q", e.Summary)
+	}
+	if !strings.Contains(e.Description, "1st Max\n2nd Lisa") {
+		t.Errorf("Expected the results in the description, got %q", e.Description)
+	}
+
+	// A cancelled game keeps its UID but is marked as cancelled with a higher sequence
+	cancelled := played
+	cancelled.Status = models.GameStatusCancelled
+	cancelled.Revision = 2
+	e = gameEvent(cancelled, "Spring 2025")
+	if e.UID != "game-12@pokerhans" || e.Status != ical.StatusCancelled || e.Sequence != 2 {
+		t.Errorf("Expected a cancelled event with sequence 2, got %+v", e)
+	}
+	if strings.Contains(e.Description, "Max") {
+		t.Errorf("Expected no results for a cancelled game, got %q", e.Description)
+	}
+
+	// Upcoming games list who is coming
+	upcoming := models.Game{
+		ID:         13,
+		HostName:   "Jonas",
+		GameDate:   date,
+		Status:     models.GameStatusPlanned,
+		Attendance: []models.Attendance{{PlayerName: "Max", Status: "yes"}, {PlayerName: "Lisa", Status: "no"}},
+	}
+	e = gameEvent(upcoming, "")
+	if e.Description != "Yes: Max" {
+		t.Errorf("Expected description %q, got %q", "Yes: Max", e.Description)
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+// CompareHandler handles the head-to-head comparison of two players. Both
+// players are picked with the query parameters a and b; season optionally
+// limits the comparison to one season.
+func (h *Handler) CompareHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: CompareHandler - Processing player comparison")
+
+	query := r.URL.Query()
+	var ids [3]int
+	for i, name := range []string{"a", "b", "season"} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			h.Logger.Printf("ERROR: Invalid %s: %s", name, value)
+			http.Error(w, "Invalid "+name+" parameter", http.StatusBadRequest)
+			return
+		}
+		ids[i] = id
+	}
+	playerA, playerB, seasonID := ids[0], ids[1], ids[2]
+
+	h.Logger.Printf("PARAM: Player A = %d, Player B = %d, Season ID = %d", playerA, playerB, seasonID)
+
+	players, err := h.Repo.GetAllPlayers()
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting players failed: %v", err)
+		http.Error(w, "Failed to load players", http.StatusInternalServerError)
+		return
+	}
+
+	seasons, err := h.Repo.GetSeasons()
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting seasons failed: %v", err)
+		http.Error(w, "Failed to load seasons", http.StatusInternalServerError)
+		return
+	}
+
+	// The comparison is only shown once two different players are picked
+	var result *models.HeadToHead
+	message := ""
+	switch {
+	case playerA == 0 || playerB == 0:
+		message = "Pick two players to compare."
+	case playerA == playerB:
+		message = "Pick two different players."
+	default:
+		h2h, err := h.Repo.GetHeadToHead(playerA, playerB, seasonID)
+		if errors.Is(err, sql.ErrNoRows) {
+			h.Logger.Printf("ERROR: Player %d or %d not found", playerA, playerB)
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			h.Logger.Printf("ERROR: Comparing players failed: %v", err)
+			http.Error(w, "Failed to compare players", http.StatusInternalServerError)
+			return
+		}
+		h.Logger.Printf("DATA: %s and %s shared %d games", h2h.PlayerA.Name, h2h.PlayerB.Name, len(h2h.Games))
+		result = &h2h
+	}
+
+	data := struct {
+		Page
+		Players  []models.Player
+		Seasons  []models.Season
+		A        int
+		B        int
+		SeasonID int
+		Message  string
+		Result   *models.HeadToHead
+	}{
+		Page:     h.newPage(w, r),
+		Players:  players,
+		Seasons:  seasons,
+		A:        playerA,
+		B:        playerB,
+		SeasonID: seasonID,
+		Message:  message,
+		Result:   result,
+	}
+
+	h.Logger.Printf("RENDER: Rendering layout template with compare content")
+	if err := h.render(w, "compare", data); err != nil {
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Compare page rendered successfully")
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FieldErrors maps form field names to the message shown next to the field.
+// Errors that belong to no single field use the key "form".
+type FieldErrors map[string]string
+
+// String joins all messages, for responses that cannot show them inline
+func (e FieldErrors) String() string {
+	messages := make([]string, 0, len(e))
+	for _, message := range e {
+		messages = append(messages, message)
+	}
+	sort.Strings(messages)
+	return strings.Join(messages, "; ")
+}
+
+// GameForm is a submitted game form that failed validation. It is handed
+// back to the season template to reopen the form's modal with the submitted
+// values and the errors.
+type GameForm struct {
+	Modal  string
+	Values url.Values
+	Errors FieldErrors
+}
+
+// GameFormRow holds the submitted values of one participant row
+type GameFormRow struct {
+	Position string
+	BuyIn    string
+	Rebuys   string
+	Payout   string
+}
+
+// For returns the form if it belongs to the given modal and nil otherwise.
+// All methods can be called on a nil form.
+func (f *GameForm) For(modal string) *GameForm {
+	if f == nil || f.Modal != modal {
+		return nil
+	}
+	return f
+}
+
+// Value returns the submitted value of a field, or fallback without a form
+func (f *GameForm) Value(name, fallback string) string {
+	if f == nil {
+		return fallback
+	}
+	return f.Values.Get(name)
+}
+
+// Error returns the error message of a field, if any
+func (f *GameForm) Error(name string) string {
+	if f == nil {
+		return ""
+	}
+	return f.Errors[name]
+}
+
+// Row returns the submitted participant row of a player
+func (f *GameForm) Row(playerID int) GameFormRow {
+	if f == nil {
+		return GameFormRow{}
+	}
+
+	id := strconv.Itoa(playerID)
+	for i, v := range f.Values["result_player_id"] {
+		if v == id {
+			return GameFormRow{
+				Position: valueAt(f.Values["result_position"], i),
+				BuyIn:    valueAt(f.Values["buy_in"], i),
+				Rebuys:   valueAt(f.Values["rebuys"], i),
+				Payout:   valueAt(f.Values["payout"], i),
+			}
+		}
+	}
+	return GameFormRow{}
+}
+
+// valueAt returns values[i], or "" if there is no such value
+func valueAt(values []string, i int) string {
+	if i < len(values) {
+		return values[i]
+	}
+	return ""
+}
+
+// renderGameForm shows the season page again with a failed game form
+func (h *Handler) renderGameForm(w http.ResponseWriter, r *http.Request, status, seasonID int, form *GameForm) {
+	h.Logger.Printf("DATA: Form %s has errors: %v", form.Modal, form.Errors)
+
+	page, err := h.loadSeasonPage(seasonID)
+	if err != nil {
+		h.Logger.Printf("ERROR: Loading season page failed: %v", err)
+		http.Error(w, "Failed to load season", http.StatusInternalServerError)
+		return
+	}
+	page.Page = h.newPage(w, r)
+	h.setRSVPPlayers(page)
+	page.Form = form
+
+	h.Logger.Printf("RENDER: Rendering season page with form errors (status %d)", status)
+	h.renderStatus(w, status, "season", page)
+}
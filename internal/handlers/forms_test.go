@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestGameFormNil(t *testing.T) {
+	var form *GameForm
+
+	if got := form.For("addGameModal-1"); got != nil {
+		t.Errorf("Expected no form, got %v", got)
+	}
+	if got := form.Value("game_date", "2025-01-01"); got != "2025-01-01" {
+		t.Errorf("Expected the fallback value, got %q", got)
+	}
+	if got := form.Error("game_date"); got != "" {
+		t.Errorf("Expected no error, got %q", got)
+	}
+	if got := form.Row(1); got != (GameFormRow{}) {
+		t.Errorf("Expected an empty row, got %+v", got)
+	}
+}
+
+func TestGameFormValues(t *testing.T) {
+	form := &GameForm{
+		Modal: "addGameModal-3",
+		Values: url.Values{
+			"game_date":        {"2025-02-01"},
+			"result_player_id": {"1", "2"},
+			"result_position":  {"", "1"},
+			"buy_in":           {"", "10"},
+			"rebuys":           {"", ""},
+			"payout":           {"", "abc"},
+		},
+		Errors: FieldErrors{"amounts": "Invalid amounts"},
+	}
+
+	if form.For("addGameModal-4") != nil {
+		t.Errorf("Expected no form for another modal")
+	}
+
+	f := form.For("addGameModal-3")
+	if f == nil {
+		t.Fatalf("Expected the form for its own modal")
+	}
+	if got := f.Value("game_date", "2025-01-01"); got != "2025-02-01" {
+		t.Errorf("Expected the submitted date, got %q", got)
+	}
+	if got := f.Error("amounts"); got != "Invalid amounts" {
+		t.Errorf("Expected the amounts error, got %q", got)
+	}
+
+	want := GameFormRow{Position: "1", BuyIn: "10", Payout: "abc"}
+	if got := f.Row(2); got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+	if got := f.Row(5); got != (GameFormRow{}) {
+		t.Errorf("Expected an empty row for a player not in the form, got %+v", got)
+	}
+}
+
+func TestParseBlackouts(t *testing.T) {
+	dates, err := parseBlackouts("2025-03-21, 2025-04-18\n2025-12-26")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(dates) != 3 || dates[2].Format("2006-01-02") != "2025-12-26" {
+		t.Errorf("Expected three dates, got %v", dates)
+	}
+
+	if dates, err := parseBlackouts("  "); err != nil || len(dates) != 0 {
+		t.Errorf("Expected no dates for blank input, got %v, %v", dates, err)
+	}
+
+	if _, err := parseBlackouts("2025-03-21, next friday"); err == nil {
+		t.Errorf("Expected an error for an invalid date")
+	}
+}
+
+func TestOptionalDate(t *testing.T) {
+	d, err := optionalDate(" 2025-03-01 ")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if d == nil || d.Format("2006-01-02") != "2025-03-01" {
+		t.Errorf("Expected 2025-03-01, got %v", d)
+	}
+
+	d, err = optionalDate("")
+	if err != nil || d != nil {
+		t.Errorf("Expected no date and no error for an empty value, got %v, %v", d, err)
+	}
+
+	if _, err := optionalDate("01.03.2025"); err == nil {
+		t.Errorf("Expected an error for an invalid date")
+	}
+}
@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klausbreyer/pokerhans/internal/flash"
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+// GameEditRow is a player in the game editor together with their place and
+// amounts in the game, if they took part
+type GameEditRow struct {
+	models.Player
+	Position string
+	BuyIn    string
+	Rebuys   string
+	Payout   string
+}
+
+// EditGameHandler shows the form for correcting a recorded game
+func (h *Handler) EditGameHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: EditGameHandler - Processing game edit view")
+
+	// Extract game ID from URL
+	path := r.URL.Path
+	re := regexp.MustCompile(`^/game/(\d+)/edit$`)
+	matches := re.FindStringSubmatch(path)
+
+	if len(matches) < 2 {
+		h.Logger.Printf("ERROR: Invalid game edit URL: %s", path)
+		http.NotFound(w, r)
+		return
+	}
+
+	gameID, err := strconv.Atoi(matches[1])
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid game ID: %s", matches[1])
+		http.Error(w, "Invalid game ID", http.StatusBadRequest)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Game ID = %d", gameID)
+
+	game, season, ok := h.loadGame(w, gameID)
+	if !ok {
+		return
+	}
+
+	if !h.authorizeSeason(w, r, game.SeasonID) {
+		return
+	}
+
+	h.renderGameEdit(w, r, http.StatusOK, game, season, nil)
+}
+
+// renderGameEdit shows the game editor. With a failed form, the submitted
+// values and the errors are shown instead of the stored game; a form without
+// values only shows its errors.
+func (h *Handler) renderGameEdit(w http.ResponseWriter, r *http.Request, status int, game models.Game, season models.Season, form *GameForm) {
+	players, err := h.Repo.GetAllPlayers()
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting players failed: %v", err)
+		http.Error(w, "Failed to load players", http.StatusInternalServerError)
+		return
+	}
+
+	dateChanges, err := h.Repo.GetGameDateChanges(game.ID)
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting date changes failed: %v", err)
+		http.Error(w, "Failed to load game", http.StatusInternalServerError)
+		return
+	}
+
+	positions := make(map[int]int, len(game.Results))
+	for _, res := range game.Results {
+		positions[res.PlayerID] = res.Position
+	}
+	ledger := make(map[int]models.LedgerEntry, len(game.Ledger))
+	for _, e := range game.Ledger {
+		ledger[e.PlayerID] = e
+	}
+
+	hostID := strconv.Itoa(game.HostID)
+	gameDate := game.GameDate.Format("2006-01-02")
+	submitted := form != nil && form.Values != nil
+	if submitted {
+		hostID = form.Value("host_id", hostID)
+		gameDate = form.Value("game_date", gameDate)
+	}
+
+	// Offer every active player, plus inactive players who hosted or played
+	var hosts []models.Player
+	var rows []GameEditRow
+	for _, p := range players {
+		entry, inLedger := ledger[p.ID]
+		if !p.Active && p.ID != game.HostID && positions[p.ID] == 0 && !inLedger {
+			continue
+		}
+
+		hosts = append(hosts, p)
+		row := GameEditRow{Player: p}
+		if submitted {
+			posted := form.Row(p.ID)
+			row.Position, row.BuyIn, row.Rebuys, row.Payout = posted.Position, posted.BuyIn, posted.Rebuys, posted.Payout
+		} else {
+			if pos := positions[p.ID]; pos > 0 {
+				row.Position = strconv.Itoa(pos)
+			}
+			if inLedger {
+				row.BuyIn = formAmount(entry.BuyIn)
+				row.Rebuys = formAmount(entry.Rebuys)
+				row.Payout = formAmount(entry.Payout)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	data := struct {
+		Page
+		Game        models.Game
+		Season      models.Season
+		Hosts       []models.Player
+		HostID      string
+		GameDate    string
+		Rows        []GameEditRow
+		DateChanges []models.GameDateChange
+		Editable    bool
+		Form        *GameForm
+	}{
+		Page:        h.newPage(w, r),
+		Game:        game,
+		Season:      season,
+		Hosts:       hosts,
+		HostID:      hostID,
+		GameDate:    gameDate,
+		Rows:        rows,
+		DateChanges: dateChanges,
+		Editable:    !season.IsArchived(),
+		Form:        form,
+	}
+
+	if form != nil {
+		h.Logger.Printf("DATA: Game %d form has errors: %v", game.ID, form.Errors)
+	}
+	h.Logger.Printf("RENDER: Rendering layout template with game edit content (status %d)", status)
+	h.renderStatus(w, status, "game_edit", data)
+}
+
+// UpdateGameHandler handles saving a corrected game
+func (h *Handler) UpdateGameHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: UpdateGameHandler - Processing game update")
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	// Parse form values
+	gameID, err := strconv.Atoi(r.FormValue("game_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid game_id: %s", r.FormValue("game_id"))
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+
+	game, season, ok := h.loadGame(w, gameID)
+	if !ok {
+		return
+	}
+
+	if !h.authorizeSeason(w, r, game.SeasonID) {
+		return
+	}
+
+	// Invalid input is shown next to the fields of the editor
+	form := &GameForm{Values: r.PostForm}
+
+	finishingOrder, ledger, errs := h.parseGameResults(r)
+
+	hostID, err := strconv.Atoi(r.FormValue("host_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid host_id: %s", r.FormValue("host_id"))
+		errs["host_id"] = "Choose a host"
+	}
+
+	gameDate, err := time.Parse("2006-01-02", r.FormValue("game_date"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid game_date format: %s", r.FormValue("game_date"))
+		errs["game_date"] = "Invalid date format"
+	}
+	if len(errs) > 0 {
+		form.Errors = errs
+		h.renderGameEdit(w, r, http.StatusBadRequest, game, season, form)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Game ID = %d, Host ID = %d, Finishing Order = %v, Ledger Entries = %d, Game Date = %s",
+		gameID, hostID, finishingOrder, len(ledger), gameDate.Format("2006-01-02"))
+
+	// Update game in database
+	err = h.Repo.UpdateGame(gameID, hostID, gameDate, finishingOrder, ledger)
+	if err != nil {
+		h.Logger.Printf("ERROR: Updating game in database: %v", err)
+		status, message := gameErrorResponse(err, "Failed to update game")
+		if status == http.StatusNotFound || status == http.StatusInternalServerError {
+			http.Error(w, message, status)
+			return
+		}
+		form.Errors = FieldErrors{gameEditErrorField(err): message}
+		h.renderGameEdit(w, r, status, game, season, form)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Game updated successfully")
+	h.Flash.Set(w, flash.LevelSuccess, "Game saved")
+
+	// Redirect back to season page
+	redirectURL := h.path("/season/" + strconv.Itoa(game.SeasonID))
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// DeleteGameHandler handles deleting a game
+func (h *Handler) DeleteGameHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: DeleteGameHandler - Processing game deletion")
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	gameID, err := strconv.Atoi(r.FormValue("game_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid game_id: %s", r.FormValue("game_id"))
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Game ID = %d", gameID)
+
+	game, season, ok := h.loadGame(w, gameID)
+	if !ok {
+		return
+	}
+
+	if !h.authorizeSeason(w, r, game.SeasonID) {
+		return
+	}
+
+	err = h.Repo.DeleteGame(gameID)
+	if err != nil {
+		h.Logger.Printf("ERROR: Deleting game from database: %v", err)
+		status, message := gameErrorResponse(err, "Failed to delete game")
+		if status == http.StatusNotFound || status == http.StatusInternalServerError {
+			http.Error(w, message, status)
+			return
+		}
+		h.renderGameEdit(w, r, status, game, season, &GameForm{Errors: FieldErrors{"form": message}})
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Game deleted successfully")
+	h.Flash.Set(w, flash.LevelSuccess, "Game deleted")
+
+	// Redirect back to season page
+	redirectURL := h.path("/season/" + strconv.Itoa(game.SeasonID))
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// loadGame loads a game and its season. It writes an error response and
+// returns false if either cannot be loaded.
+func (h *Handler) loadGame(w http.ResponseWriter, gameID int) (models.Game, models.Season, bool) {
+	game, err := h.Repo.GetGame(gameID)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: Game %d not found", gameID)
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return models.Game{}, models.Season{}, false
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting game failed: %v", err)
+		http.Error(w, "Failed to load game", http.StatusInternalServerError)
+		return models.Game{}, models.Season{}, false
+	}
+
+	season, err := h.Repo.GetSeason(game.SeasonID)
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting season failed: %v", err)
+		http.Error(w, "Failed to load season", http.StatusInternalServerError)
+		return models.Game{}, models.Season{}, false
+	}
+
+	return game, season, true
+}
+
+// parseGameResults reads the finishing order and ledger of a game form.
+// Input that cannot be parsed is reported under the "results" and "amounts"
+// fields.
+func (h *Handler) parseGameResults(r *http.Request) ([]int, []models.LedgerEntry, FieldErrors) {
+	errs := FieldErrors{}
+
+	// Handle the finishing order (optional)
+	finishingOrder, err := parseFinishingOrder(r)
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid finishing order: %v", err)
+		errs["results"] = "Invalid finishing order: " + err.Error()
+	}
+
+	// Handle the buy-ins, rebuys and payouts (optional)
+	ledger, err := parseLedger(r)
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid ledger: %v", err)
+		errs["amounts"] = "Invalid amounts: " + err.Error()
+	}
+
+	return finishingOrder, ledger, errs
+}
+
+// gameErrorResponse returns the status code and message for an error from
+// writing a game. Validation errors become client errors; anything else is
+// reported as an internal error with the given fallback message.
+func gameErrorResponse(err error, fallback string) (int, string) {
+	switch {
+	case errors.Is(err, models.ErrUnknownGame):
+		return http.StatusNotFound, "Game not found"
+	case errors.Is(err, models.ErrUnknownSeason):
+		return http.StatusNotFound, "Season not found"
+	case errors.Is(err, models.ErrUnknownPlayer):
+		return http.StatusBadRequest, "Unknown player"
+	case errors.Is(err, models.ErrSamePlayerTwice):
+		return http.StatusBadRequest, "The same player cannot be listed twice"
+	case errors.Is(err, models.ErrUnbalancedLedger):
+		return http.StatusBadRequest, "Payouts must equal the pot of buy-ins and rebuys"
+	case errors.Is(err, models.ErrDuplicateHost):
+		return http.StatusConflict, "This player has already hosted a game this season"
+	case errors.Is(err, models.ErrSeasonArchived):
+		return http.StatusConflict, "Season is archived"
+	case errors.Is(err, models.ErrDateOutsideSeason):
+		return http.StatusBadRequest, "The date must fall within the season's dates"
+	case errors.Is(err, models.ErrInvalidTransition):
+		return http.StatusConflict, "This game cannot be changed that way"
+	case errors.Is(err, models.ErrInvalidRSVP):
+		return http.StatusBadRequest, "Answer yes, no or maybe"
+	case errors.Is(err, models.ErrRSVPClosed):
+		return http.StatusConflict, "Answers are only possible before the game"
+	case errors.Is(err, models.ErrNotOnRoster):
+		return http.StatusBadRequest, "This player is not on the season's roster"
+	case errors.Is(err, models.ErrNotInSettlement):
+		return http.StatusBadRequest, "Both players must have taken part in the season or game"
+	}
+	return http.StatusInternalServerError, fallback
+}
+
+// gameErrorField returns the form field a validation error belongs to
+func gameErrorField(err error) string {
+	switch {
+	case errors.Is(err, models.ErrUnbalancedLedger):
+		return "amounts"
+	case errors.Is(err, models.ErrSamePlayerTwice), errors.Is(err, models.ErrUnknownPlayer):
+		return "results"
+	case errors.Is(err, models.ErrDateOutsideSeason):
+		return "game_date"
+	}
+	return "form"
+}
+
+// gameEditErrorField returns the field of the game editor a validation
+// error belongs to. Unlike the add form, the editor lets the host be changed.
+func gameEditErrorField(err error) string {
+	if errors.Is(err, models.ErrDuplicateHost) {
+		return "host_id"
+	}
+	return gameErrorField(err)
+}
+
+// formAmount formats a non-negative amount for an input field, e.g. "12.50"
+func formAmount(c models.Cents) string {
+	return strings.TrimPrefix(c.String(), "€")
+}
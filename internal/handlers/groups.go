@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/klausbreyer/pokerhans/internal/flash"
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+// groupPrefix starts the URLs of all pages of a group, followed by its slug
+const groupPrefix = "/g/"
+
+// scopedKey is the context key of the handler scoped to the group of a request
+type scopedKey struct{}
+
+// ForGroup returns a copy of the handler whose repository, links and
+// redirects are scoped to the given group
+func (h *Handler) ForGroup(g models.Group) *Handler {
+	scoped := *h
+	scoped.Group = g
+	scoped.Repo = h.Repo.ForGroup(g.ID)
+	return &scoped
+}
+
+// Scoped returns the handler scoped to the group of a request served by
+// GroupRoutes
+func Scoped(r *http.Request) *Handler {
+	h, _ := r.Context().Value(scopedKey{}).(*Handler)
+	return h
+}
+
+// GroupRoutes serves the pages of a group below /g/{slug}. It looks up the
+// group, removes the prefix from the path and hands the request to routes,
+// which get the handler scoped to the group with Scoped.
+func (h *Handler) GroupRoutes(routes http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest := strings.TrimPrefix(r.URL.Path, groupPrefix)
+		slug, path, _ := strings.Cut(rest, "/")
+		path = "/" + path
+
+		group, err := h.Repo.GetGroupBySlug(slug)
+		if errors.Is(err, sql.ErrNoRows) {
+			h.Logger.Printf("ERROR: Group %q not found", slug)
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			h.Logger.Printf("ERROR: Getting group failed: %v", err)
+			http.Error(w, "Failed to load group", http.StatusInternalServerError)
+			return
+		}
+		h.Logger.Printf("GROUP: %s (%d), PATH: %s", group.Slug, group.ID, path)
+
+		scoped := r.Clone(context.WithValue(r.Context(), scopedKey{}, h.ForGroup(group)))
+		scoped.URL.Path = path
+		scoped.URL.RawPath = ""
+		routes.ServeHTTP(w, scoped)
+	})
+}
+
+// path returns the URL of a page of the handler's group, e.g. /g/hans/season/1
+// for /season/1. Outside of a group it returns the path unchanged.
+func (h *Handler) path(p string) string {
+	if h.Group.ID == 0 {
+		return p
+	}
+	return groupPrefix + h.Group.Slug + p
+}
+
+// GroupsHandler handles the start page. With a single group it opens that
+// group; otherwise it lists the groups.
+func (h *Handler) GroupsHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: GroupsHandler - Getting groups")
+
+	groups, err := h.Repo.GetGroups()
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting groups failed: %v", err)
+		http.Error(w, "Failed to load groups", http.StatusInternalServerError)
+		return
+	}
+	h.Logger.Printf("DATA: Found %d groups", len(groups))
+
+	if len(groups) == 1 && r.URL.Path == "/" {
+		redirectURL := groupPrefix + groups[0].Slug + "/"
+		h.Logger.Printf("REDIRECT: To %s", redirectURL)
+		http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+		return
+	}
+
+	data := struct {
+		Page
+		Groups []models.Group
+	}{
+		Page:   h.newPage(w, r),
+		Groups: groups,
+	}
+
+	h.Logger.Printf("RENDER: Rendering layout template with groups content")
+	h.render(w, "groups", data)
+}
+
+// CreateGroupHandler handles creating a new group
+func (h *Handler) CreateGroupHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: CreateGroupHandler - Processing group creation")
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	slug := strings.ToLower(strings.TrimSpace(r.FormValue("slug")))
+	if name == "" {
+		h.Logger.Printf("ERROR: Empty group name")
+		http.Error(w, "Group name is required", http.StatusBadRequest)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Slug = %s, Name = %s", slug, name)
+
+	groupID, err := h.Repo.CreateGroup(slug, name)
+	if errors.Is(err, models.ErrInvalidSlug) || errors.Is(err, models.ErrDuplicateSlug) {
+		h.Logger.Printf("ERROR: Cannot use slug %q: %v", slug, err)
+		message := "The address must be 2 to 64 lowercase letters, digits or dashes"
+		if errors.Is(err, models.ErrDuplicateSlug) {
+			message = "The address /g/" + slug + " is already taken"
+		}
+		h.Flash.Set(w, flash.LevelError, message)
+		http.Redirect(w, r, "/groups", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Creating group in database: %v", err)
+		http.Error(w, "Failed to create group", http.StatusInternalServerError)
+		return
+	}
+
+	// The member who created the group is its first admin
+	if member := h.currentMember(r); member != nil {
+		if err := h.Repo.ForGroup(groupID).AddGroupMember(member.ID, models.RoleAdmin); err != nil {
+			h.Logger.Printf("ERROR: Adding member to group: %v", err)
+			http.Error(w, "Failed to add you to the group", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	h.Logger.Printf("SUCCESS: Group %s created successfully", slug)
+	h.Flash.Set(w, flash.LevelSuccess, "Created group "+name)
+
+	redirectURL := groupPrefix + slug + "/admin/players"
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// legacyPathPattern matches the season and player URLs from before groups
+var legacyPathPattern = regexp.MustCompile(`^/(season|player)/(\d+)(/.*)?$`)
+
+// LegacyRedirectHandler redirects season and player URLs without a group,
+// such as calendar subscriptions made before groups existed, to the same
+// page of the group they belong to. It answers 404 for all other paths.
+func (h *Handler) LegacyRedirectHandler(w http.ResponseWriter, r *http.Request) {
+	matches := legacyPathPattern.FindStringSubmatch(r.URL.Path)
+	if matches == nil {
+		h.Logger.Printf("HANDLER: NotFound (404)")
+		http.NotFound(w, r)
+		return
+	}
+
+	id, err := strconv.Atoi(matches[2])
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var group models.Group
+	if matches[1] == "season" {
+		group, err = h.Repo.GetSeasonGroup(id)
+	} else {
+		group, err = h.Repo.GetPlayerGroup(id)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: No group found for %s", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting group failed: %v", err)
+		http.Error(w, "Failed to load group", http.StatusInternalServerError)
+		return
+	}
+
+	redirectURL := groupPrefix + group.Slug + r.URL.Path
+	if r.URL.RawQuery != "" {
+		redirectURL += "?" + r.URL.RawQuery
+	}
+	h.Logger.Printf("REDIRECT: Legacy URL to %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusMovedPermanently)
+}
@@ -1,19 +1,25 @@
 package handlers
 
 import (
-	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
+	"errors"
 	"fmt"
 	"html/template"
 	"log"
 	"net/http"
-	"path/filepath"
 	"regexp"
-	"sort"
 	"strconv"
 	"time"
 
+	"github.com/klausbreyer/pokerhans/internal/config"
+	"github.com/klausbreyer/pokerhans/internal/csrf"
+	"github.com/klausbreyer/pokerhans/internal/flash"
 	"github.com/klausbreyer/pokerhans/internal/models"
+	"github.com/klausbreyer/pokerhans/internal/rating"
+	"github.com/klausbreyer/pokerhans/internal/suggest"
 )
 
 // Handler holds dependencies for the handlers
@@ -21,45 +27,85 @@ type Handler struct {
 	Logger *log.Logger
 	DB     *sql.DB
 	Repo   *models.Repository
-	Tmpl   *template.Template
-}
+	Points models.PointsScheme
+	Rating rating.Params
 
-// New creates a new Handler
-func New(logger *log.Logger, db *sql.DB) *Handler {
-	// Parse templates
-	templatesPath := filepath.Join("web", "templates", "*.html")
-	logger.Printf("DEBUG: Loading templates from %s", templatesPath)
+	// Suggest weighs the factors of the next host suggestion
+	Suggest suggest.Weights
+	Flash   *flash.Store
 
-	templates, err := filepath.Glob(templatesPath)
-	if err != nil {
-		logger.Printf("ERROR: Failed to glob templates: %v", err)
-		// Continue with empty list if glob fails
-		templates = []string{}
-	}
+	// CSRF hands out and checks the tokens of all forms
+	CSRF *csrf.Protector
 
-	// Log found templates
-	for _, t := range templates {
-		logger.Printf("DEBUG: Found template file: %s", t)
-	}
+	// Templates maps each page name to its template set, parsed together
+	// with the shared layout
+	Templates map[string]*template.Template
 
-	// Simplify template parsing - use Must to panic on errors
-	logger.Printf("DEBUG: Using simple template.ParseGlob approach")
-	tmpl := template.Must(template.ParseGlob(templatesPath))
+	// Group is the group the handler is scoped to with ForGroup; Repo only
+	// sees the data of this group
+	Group models.Group
 
-	// Log the templates we found
-	logger.Printf("DEBUG: Templates found:")
-	for _, t := range tmpl.Templates() {
-		logger.Printf("DEBUG:   - Template: %s", t.Name())
+	// SessionTTL is how long a member stays logged in
+	SessionTTL time.Duration
+}
+
+// New creates a new Handler
+func New(logger *log.Logger, db *sql.DB) *Handler {
+	pointsConfig := config.GetPointsConfig()
+	logger.Printf("DEBUG: Points scheme: win = %d, second = %d", pointsConfig.Win, pointsConfig.Second)
+
+	ratingConfig := config.GetRatingConfig()
+	logger.Printf("DEBUG: Rating: K = %d, start = %d", ratingConfig.K, ratingConfig.Start)
+
+	suggestConfig := config.GetSuggestConfig()
+	logger.Printf("DEBUG: Host suggestion weights: week = %d, hosted = %d, final = %d",
+		suggestConfig.PerWeek, suggestConfig.PerHosting, suggestConfig.LastFinal)
+
+	secret := config.GetSessionSecret()
+	if secret == nil {
+		// Without a configured secret, cookies only stay valid until restart
+		logger.Printf("WARNING: SESSION_SECRET is not set, using a random secret")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			logger.Fatalf("Failed to generate session secret: %v", err)
+		}
 	}
 
+	sessionDays := config.GetSessionDays()
+	logger.Printf("DEBUG: Sessions last %d days", sessionDays)
+
 	return &Handler{
-		Logger: logger,
-		DB:     db,
-		Repo:   models.NewRepository(db),
-		Tmpl:   tmpl,
+		Logger:     logger,
+		DB:         db,
+		Repo:       models.NewRepository(db),
+		Templates:  loadTemplates(logger),
+		Flash:      flash.New(deriveKey(secret, "flash")),
+		CSRF:       csrf.New(deriveKey(secret, "csrf")),
+		SessionTTL: time.Duration(sessionDays) * 24 * time.Hour,
+		Points: models.PointsScheme{
+			Win:    pointsConfig.Win,
+			Second: pointsConfig.Second,
+		},
+		Rating: rating.Params{
+			K:     float64(ratingConfig.K),
+			Start: float64(ratingConfig.Start),
+		},
+		Suggest: suggest.Weights{
+			PerWeek:    float64(suggestConfig.PerWeek),
+			PerHosting: float64(suggestConfig.PerHosting),
+			LastFinal:  float64(suggestConfig.LastFinal),
+		},
 	}
 }
 
+// deriveKey returns the key for one use of the session secret, so that a
+// value signed for one purpose is never accepted for another
+func deriveKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
 // HomeHandler handles the root route
 func (h *Handler) HomeHandler(w http.ResponseWriter, r *http.Request) {
 	h.Logger.Printf("ACTION: HomeHandler - Getting seasons")
@@ -73,9 +119,21 @@ func (h *Handler) HomeHandler(w http.ResponseWriter, r *http.Request) {
 
 	h.Logger.Printf("DATA: Found %d seasons", len(seasons))
 
-	// If there are seasons, redirect to the first one
+	// Redirect to the current season, or to the newest one if all are archived
+	current, err := h.Repo.GetCurrentSeason()
+	if err == nil {
+		redirectURL := h.path("/season/" + strconv.Itoa(current.ID))
+		h.Logger.Printf("REDIRECT: To current season %s", redirectURL)
+		http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: Getting current season failed: %v", err)
+		http.Error(w, "Failed to load seasons", http.StatusInternalServerError)
+		return
+	}
 	if len(seasons) > 0 {
-		redirectURL := "/season/" + strconv.Itoa(seasons[0].ID)
+		redirectURL := h.path("/season/" + strconv.Itoa(seasons[0].ID))
 		h.Logger.Printf("REDIRECT: To %s", redirectURL)
 		http.Redirect(w, r, redirectURL, http.StatusSeeOther)
 		return
@@ -84,39 +142,14 @@ func (h *Handler) HomeHandler(w http.ResponseWriter, r *http.Request) {
 	// No seasons, render empty home page
 	h.Logger.Printf("RENDER: Rendering layout template with home content")
 	data := struct {
-		CurrentYear int
+		Page
+		Seasons []models.Season
 	}{
-		CurrentYear: time.Now().Year(),
-	}
-
-	// Capture the template output to inspect it
-	var buf bytes.Buffer
-	err = h.Tmpl.ExecuteTemplate(&buf, "layout", data)
-	if err != nil {
-		h.Logger.Printf("ERROR: Template rendering failed: %v", err)
-		http.Error(w, "Failed to render template", http.StatusInternalServerError)
-		return
+		Page:    h.newPage(w, r),
+		Seasons: seasons,
 	}
 
-	output := buf.String()
-	h.Logger.Printf("HOME_TEMPLATE_OUTPUT_LENGTH: %d bytes", len(output))
-	if len(output) == 0 {
-		h.Logger.Printf("ERROR: Home template output is empty!")
-	} else if len(output) < 50 {
-		h.Logger.Printf("HOME_TEMPLATE_OUTPUT: %s", output) // Log only if it's short
-	} else {
-		h.Logger.Printf("HOME_TEMPLATE_OUTPUT_FIRST_50: %s...", output[:50])
-	}
-
-	// Set Content-Type header
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-
-	// Write the output to the response
-	_, err = w.Write(buf.Bytes())
-	if err != nil {
-		h.Logger.Printf("ERROR: Failed to write home response: %v", err)
-		return
-	}
+	h.render(w, "home", data)
 }
 
 // SeasonHandler handles the season view
@@ -143,137 +176,17 @@ func (h *Handler) SeasonHandler(w http.ResponseWriter, r *http.Request) {
 
 	h.Logger.Printf("PARAM: Season ID = %d", seasonID)
 
-	// Get all seasons for the dropdown
-	seasons, err := h.Repo.GetSeasons()
-	if err != nil {
-		h.Logger.Printf("ERROR: Getting seasons failed: %v", err)
-		http.Error(w, "Failed to load seasons", http.StatusInternalServerError)
-		return
-	}
-	h.Logger.Printf("DATA: Found %d seasons total", len(seasons))
-
-	// Get players for this season
-	players, err := h.Repo.GetSeasonPlayers(seasonID)
-	if err != nil {
-		h.Logger.Printf("ERROR: Getting players failed: %v", err)
-		http.Error(w, "Failed to load players", http.StatusInternalServerError)
-		return
-	}
-	h.Logger.Printf("DATA: Found %d players for season %d", len(players), seasonID)
-
-	// Get games for this season
-	games, err := h.Repo.GetGames(seasonID)
+	page, err := h.loadSeasonPage(seasonID)
 	if err != nil {
-		h.Logger.Printf("ERROR: Getting games failed: %v", err)
-		http.Error(w, "Failed to load games", http.StatusInternalServerError)
+		h.Logger.Printf("ERROR: Loading season page failed: %v", err)
+		http.Error(w, "Failed to load season", http.StatusInternalServerError)
 		return
 	}
-	h.Logger.Printf("DATA: Found %d games for season %d", len(games), seasonID)
-
-	// Reverse the order of games to show oldest first
-	sort.Slice(games, func(i, j int) bool {
-		return games[i].GameDate.Before(games[j].GameDate)
-	})
-
-	// Separate players into visited and not visited
-	var visited, notVisited []models.PlayerStatus
-	for _, p := range players {
-		if p.HasHosted {
-			visited = append(visited, p)
-		} else {
-			notVisited = append(notVisited, p)
-		}
-	}
-
-	// Sort visited players by game date (oldest first)
-	sort.Slice(visited, func(i, j int) bool {
-		return visited[i].GameDate.Before(visited[j].GameDate)
-	})
-
-	// Sort players to visit by created_at date (oldest first)
-	sort.Slice(notVisited, func(i, j int) bool {
-		return notVisited[i].CreatedAt.Before(notVisited[j].CreatedAt)
-	})
-	h.Logger.Printf("DATA: %d players visited, %d players to visit", len(visited), len(notVisited))
-
-	// Get all players for the dropdowns
-	allPlayers, err := h.Repo.GetAllPlayers()
-	if err != nil {
-		h.Logger.Printf("ERROR: Getting all players failed: %v", err)
-		http.Error(w, "Failed to load players", http.StatusInternalServerError)
-		return
-	}
-	h.Logger.Printf("DATA: Found %d total players in system", len(allPlayers))
-
-	// Current season
-	var currentSeason models.Season
-	for _, s := range seasons {
-		if s.ID == seasonID {
-			currentSeason = s
-			break
-		}
-	}
-	h.Logger.Printf("DATA: Current season name: %s", currentSeason.Name)
-
-	// Determine if this is the latest season (highest ID)
-	var highestID int
-	for _, s := range seasons {
-		if s.ID > highestID {
-			highestID = s.ID
-		}
-	}
-	isLatestSeason := seasonID == highestID
-
-	data := struct {
-		Seasons        []models.Season
-		CurrentSeason  models.Season
-		VisitedPlayers []models.PlayerStatus
-		ToVisitPlayers []models.PlayerStatus
-		Games          []models.Game
-		AllPlayers     []models.Player
-		CurrentDate    string
-		CurrentYear    int
-		IsLatestSeason bool
-	}{
-		Seasons:        seasons,
-		CurrentSeason:  currentSeason,
-		VisitedPlayers: visited,
-		ToVisitPlayers: notVisited,
-		Games:          games,
-		AllPlayers:     allPlayers,
-		CurrentDate:    time.Now().Format("2006-01-02"),
-		CurrentYear:    time.Now().Year(),
-		IsLatestSeason: isLatestSeason,
-	}
+	page.Page = h.newPage(w, r)
+	h.setRSVPPlayers(page)
 
 	h.Logger.Printf("RENDER: Rendering layout template with season content")
-
-	// Capture the template output to inspect it
-	var buf bytes.Buffer
-	err = h.Tmpl.ExecuteTemplate(&buf, "layout", data)
-	if err != nil {
-		h.Logger.Printf("ERROR: Template rendering failed: %v", err)
-		http.Error(w, "Failed to render template", http.StatusInternalServerError)
-		return
-	}
-
-	output := buf.String()
-	h.Logger.Printf("TEMPLATE_OUTPUT_LENGTH: %d bytes", len(output))
-	if len(output) == 0 {
-		h.Logger.Printf("ERROR: Template output is empty!")
-	} else if len(output) < 50 {
-		h.Logger.Printf("TEMPLATE_OUTPUT: %s", output) // Log only if it's short
-	} else {
-		h.Logger.Printf("TEMPLATE_OUTPUT_FIRST_50: %s...", output[:50])
-	}
-
-	// Set Content-Type header
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-
-	// Write the output to the response
-	_, err = w.Write(buf.Bytes())
-	if err != nil {
-		h.Logger.Printf("ERROR: Failed to write response: %v", err)
+	if err := h.render(w, "season", page); err != nil {
 		return
 	}
 
@@ -298,6 +211,10 @@ func (h *Handler) AddGameHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorizeSeason(w, r, seasonID) {
+		return
+	}
+
 	hostID, err := strconv.Atoi(r.FormValue("host_id"))
 	if err != nil {
 		h.Logger.Printf("ERROR: Invalid host_id: %s", r.FormValue("host_id"))
@@ -305,68 +222,51 @@ func (h *Handler) AddGameHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Handle winner_id (optional)
-	var winnerID *int
-	winnerIDStr := r.FormValue("winner_id")
-	if winnerIDStr != "" {
-		winVal, err := strconv.Atoi(winnerIDStr)
-		if err != nil {
-			h.Logger.Printf("ERROR: Invalid winner_id: %s", winnerIDStr)
-			http.Error(w, "Invalid winner ID", http.StatusBadRequest)
-			return
-		}
-		winnerID = &winVal
+	// Invalid input is shown next to the fields of the reopened form
+	form := &GameForm{
+		Modal:  fmt.Sprintf("addGameModal-%d", hostID),
+		Values: r.PostForm,
 	}
 
-	// Handle second_place_id (optional)
-	var secondPlaceID *int
-	secondPlaceIDStr := r.FormValue("second_place_id")
-	if secondPlaceIDStr != "" {
-		secondVal, err := strconv.Atoi(secondPlaceIDStr)
-		if err != nil {
-			h.Logger.Printf("ERROR: Invalid second_place_id: %s", secondPlaceIDStr)
-			http.Error(w, "Invalid second place ID", http.StatusBadRequest)
-			return
-		}
-		secondPlaceID = &secondVal
-	}
+	finishingOrder, ledger, errs := h.parseGameResults(r)
 
 	gameDate, err := time.Parse("2006-01-02", r.FormValue("game_date"))
 	if err != nil {
 		h.Logger.Printf("ERROR: Invalid game_date format: %s", r.FormValue("game_date"))
-		http.Error(w, "Invalid date format", http.StatusBadRequest)
-		return
+		errs["game_date"] = "Invalid date format"
 	}
-
-	// Log parameters with pointer-safe handling
-	// Create string representations for logging
-	var winnerIDLogStr, secondPlaceIDLogStr string
-
-	winnerIDLogStr = "null"
-	if winnerID != nil {
-		winnerIDLogStr = fmt.Sprintf("%d", *winnerID)
-	}
-
-	secondPlaceIDLogStr = "null"
-	if secondPlaceID != nil {
-		secondPlaceIDLogStr = fmt.Sprintf("%d", *secondPlaceID)
+	if len(errs) > 0 {
+		form.Errors = errs
+		h.renderGameForm(w, r, http.StatusBadRequest, seasonID, form)
+		return
 	}
 
-	h.Logger.Printf("PARAM: Season ID = %d, Host ID = %d, Winner ID = %s, Second Place ID = %s, Game Date = %s",
-		seasonID, hostID, winnerIDLogStr, secondPlaceIDLogStr, gameDate.Format("2006-01-02"))
+	h.Logger.Printf("PARAM: Season ID = %d, Host ID = %d, Finishing Order = %v, Ledger Entries = %d, Game Date = %s",
+		seasonID, hostID, finishingOrder, len(ledger), gameDate.Format("2006-01-02"))
 
 	// Add game to database
-	err = h.Repo.AddGame(seasonID, hostID, winnerID, secondPlaceID, gameDate)
+	err = h.Repo.AddGame(seasonID, hostID, gameDate, finishingOrder, ledger)
 	if err != nil {
 		h.Logger.Printf("ERROR: Adding game to database: %v", err)
-		http.Error(w, "Failed to add game", http.StatusInternalServerError)
+		status, message := gameErrorResponse(err, "Failed to add game")
+		if status == http.StatusNotFound || status == http.StatusInternalServerError {
+			http.Error(w, message, status)
+			return
+		}
+		form.Errors = FieldErrors{gameErrorField(err): message}
+		h.renderGameForm(w, r, status, seasonID, form)
 		return
 	}
 
 	h.Logger.Printf("SUCCESS: Game added successfully")
+	if len(finishingOrder) == 0 {
+		h.Flash.Set(w, flash.LevelWarning, "Game saved without results")
+	} else {
+		h.Flash.Set(w, flash.LevelSuccess, "Game saved")
+	}
 
 	// Redirect back to season page
-	redirectURL := "/season/" + strconv.Itoa(seasonID)
+	redirectURL := h.path("/season/" + strconv.Itoa(seasonID))
 	h.Logger.Printf("REDIRECT: To %s", redirectURL)
 	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
 }
@@ -389,17 +289,24 @@ func (h *Handler) UpdateGameDateHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	seasonID, err := strconv.Atoi(r.FormValue("season_id"))
-	if err != nil {
-		h.Logger.Printf("ERROR: Invalid season_id: %s", r.FormValue("season_id"))
-		http.Error(w, "Invalid season ID", http.StatusBadRequest)
+	// The season is the game's own, whatever page the form was sent from
+	game, ok := h.authorizeGame(w, r, gameID)
+	if !ok {
 		return
 	}
+	seasonID := game.SeasonID
+
+	// Invalid input is shown next to the field of the reopened form
+	form := &GameForm{
+		Modal:  fmt.Sprintf("editGameModal-%d", gameID),
+		Values: r.PostForm,
+	}
 
 	newDate, err := time.Parse("2006-01-02", r.FormValue("new_date"))
 	if err != nil {
 		h.Logger.Printf("ERROR: Invalid new_date format: %s", r.FormValue("new_date"))
-		http.Error(w, "Invalid date format", http.StatusBadRequest)
+		form.Errors = FieldErrors{"new_date": "Invalid date format"}
+		h.renderGameForm(w, r, http.StatusBadRequest, seasonID, form)
 		return
 	}
 
@@ -410,14 +317,21 @@ func (h *Handler) UpdateGameDateHandler(w http.ResponseWriter, r *http.Request)
 	err = h.Repo.UpdateGameDate(gameID, newDate)
 	if err != nil {
 		h.Logger.Printf("ERROR: Updating game date in database: %v", err)
-		http.Error(w, "Failed to update game date", http.StatusInternalServerError)
+		status, message := gameErrorResponse(err, "Failed to update game date")
+		if status == http.StatusNotFound || status == http.StatusInternalServerError {
+			http.Error(w, message, status)
+			return
+		}
+		form.Errors = FieldErrors{"form": message}
+		h.renderGameForm(w, r, status, seasonID, form)
 		return
 	}
 
 	h.Logger.Printf("SUCCESS: Game date updated successfully")
+	h.Flash.Set(w, flash.LevelSuccess, "Game moved to "+newDate.Format("Jan 02, 2006"))
 
 	// Redirect back to season page
-	redirectURL := "/season/" + strconv.Itoa(seasonID)
+	redirectURL := h.path("/season/" + strconv.Itoa(seasonID))
 	h.Logger.Printf("REDIRECT: To %s", redirectURL)
 	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
 }
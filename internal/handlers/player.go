@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/klausbreyer/pokerhans/internal/models"
+	"github.com/klausbreyer/pokerhans/internal/rating"
+)
+
+// recentGamesLimit is the number of recent results shown on a player profile
+const recentGamesLimit = 5
+
+// PlayerHandler handles the player profile view
+func (h *Handler) PlayerHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: PlayerHandler - Processing player profile")
+
+	// Extract player ID from URL
+	path := r.URL.Path
+	re := regexp.MustCompile(`^/player/(\d+)$`)
+	matches := re.FindStringSubmatch(path)
+
+	if len(matches) < 2 {
+		h.Logger.Printf("ERROR: Invalid player URL: %s", path)
+		http.NotFound(w, r)
+		return
+	}
+
+	playerID, err := strconv.Atoi(matches[1])
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid player ID: %s", matches[1])
+		http.Error(w, "Invalid player ID", http.StatusBadRequest)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Player ID = %d", playerID)
+
+	stats, err := h.Repo.GetPlayerStats(playerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: Player %d not found", playerID)
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting player stats failed: %v", err)
+		http.Error(w, "Failed to load player", http.StatusInternalServerError)
+		return
+	}
+	h.Logger.Printf("DATA: Player %s played %d games", stats.Name, stats.GamesPlayed)
+
+	recentGames, err := h.Repo.GetPlayerRecentGames(playerID, recentGamesLimit)
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting recent games failed: %v", err)
+		http.Error(w, "Failed to load recent games", http.StatusInternalServerError)
+		return
+	}
+	h.Logger.Printf("DATA: Found %d recent games for player %d", len(recentGames), playerID)
+
+	history, err := h.replayRatings()
+	if err != nil {
+		h.Logger.Printf("ERROR: Computing ratings failed: %v", err)
+		http.Error(w, "Failed to compute ratings", http.StatusInternalServerError)
+		return
+	}
+
+	// Find the player's line and rank in the ratings table
+	var entry *rating.Entry
+	rank := 0
+	for i, e := range history.Table() {
+		if e.PlayerID == playerID {
+			entry, rank = &e, i+1
+			break
+		}
+	}
+
+	// The latest rating changes are listed newest first
+	points := history[playerID]
+	var recentRatings []rating.Point
+	for i := len(points) - 1; i >= 0 && len(recentRatings) < recentGamesLimit; i-- {
+		recentRatings = append(recentRatings, points[i])
+	}
+	h.Logger.Printf("DATA: Player %d has %d rated games", playerID, len(points))
+
+	data := struct {
+		Page
+		Stats         models.PlayerStats
+		RecentGames   []models.PlayerGame
+		Rating        *rating.Entry
+		RatingRank    int
+		RatingChart   string
+		RecentRatings []rating.Point
+	}{
+		Page:          h.newPage(w, r),
+		Stats:         stats,
+		RecentGames:   recentGames,
+		Rating:        entry,
+		RatingRank:    rank,
+		RatingChart:   ratingChart(points),
+		RecentRatings: recentRatings,
+	}
+
+	h.Logger.Printf("RENDER: Rendering layout template with player content")
+	if err := h.render(w, "player", data); err != nil {
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Player page rendered successfully")
+}
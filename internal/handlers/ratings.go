@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/klausbreyer/pokerhans/internal/models"
+	"github.com/klausbreyer/pokerhans/internal/rating"
+)
+
+// Size of the rating chart on the player page, in SVG user units
+const (
+	ratingChartWidth  = 600
+	ratingChartHeight = 120
+)
+
+// RatingRow is a line of the ratings table
+type RatingRow struct {
+	rating.Entry
+	Rank   int
+	Name   string
+	Active bool
+}
+
+// RatingsHandler handles the table of skill ratings
+func (h *Handler) RatingsHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: RatingsHandler - Processing ratings table")
+
+	history, err := h.replayRatings()
+	if err != nil {
+		h.Logger.Printf("ERROR: Computing ratings failed: %v", err)
+		http.Error(w, "Failed to compute ratings", http.StatusInternalServerError)
+		return
+	}
+
+	players, err := h.Repo.GetAllPlayers()
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting players failed: %v", err)
+		http.Error(w, "Failed to load players", http.StatusInternalServerError)
+		return
+	}
+	byID := make(map[int]models.Player, len(players))
+	for _, p := range players {
+		byID[p.ID] = p
+	}
+
+	var rows []RatingRow
+	for i, e := range history.Table() {
+		p := byID[e.PlayerID]
+		rows = append(rows, RatingRow{Entry: e, Rank: i + 1, Name: p.Name, Active: p.Active})
+	}
+	h.Logger.Printf("DATA: %d rated players", len(rows))
+
+	data := struct {
+		Page
+		Rows   []RatingRow
+		Params rating.Params
+	}{
+		Page:   h.newPage(w, r),
+		Rows:   rows,
+		Params: h.Rating,
+	}
+
+	h.Logger.Printf("RENDER: Rendering layout template with ratings content")
+	if err := h.render(w, "ratings", data); err != nil {
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Ratings page rendered successfully")
+}
+
+// replayRatings rates all played games from the first one on
+func (h *Handler) replayRatings() (rating.History, error) {
+	games, err := h.Repo.GetPlayedGames()
+	if err != nil {
+		return nil, err
+	}
+	h.Logger.Printf("DATA: Rating %d played games", len(games))
+
+	rated := make([]rating.Game, len(games))
+	for i, g := range games {
+		rated[i] = ratingGame(g)
+	}
+	return rating.Replay(rated, h.Rating), nil
+}
+
+// ratingGame takes the ranks of a game from its finishing order. Players who
+// attended but were not ranked share the place after the last ranked player.
+// A game without a finishing order has no ranks.
+func ratingGame(g models.Game) rating.Game {
+	rg := rating.Game{ID: g.ID, Date: g.GameDate}
+	if len(g.Results) == 0 {
+		return rg
+	}
+
+	ranked := make(map[int]bool, len(g.Results))
+	for _, res := range g.Results {
+		ranked[res.PlayerID] = true
+		rg.Placings = append(rg.Placings, rating.Placing{PlayerID: res.PlayerID, Rank: res.Position})
+	}
+
+	last := g.Results[len(g.Results)-1].Position + 1
+	for _, a := range g.AttendeesWith(models.AttendanceAttended) {
+		if !ranked[a.PlayerID] {
+			rg.Placings = append(rg.Placings, rating.Placing{PlayerID: a.PlayerID, Rank: last})
+		}
+	}
+
+	return rg
+}
+
+// ratingChart returns the points attribute of an SVG polyline drawing the
+// ratings over time, scaled to the chart size
+func ratingChart(points []rating.Point) string {
+	if len(points) < 2 {
+		return ""
+	}
+
+	low, high := points[0].Rating, points[0].Rating
+	for _, p := range points {
+		low = math.Min(low, p.Rating)
+		high = math.Max(high, p.Rating)
+	}
+	span := high - low
+	if span == 0 {
+		span = 1
+	}
+
+	coords := make([]string, len(points))
+	step := float64(ratingChartWidth) / float64(len(points)-1)
+	for i, p := range points {
+		x := float64(i) * step
+		y := ratingChartHeight - (p.Rating-low)/span*ratingChartHeight
+		coords[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	return strings.Join(coords, " ")
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/klausbreyer/pokerhans/internal/models"
+	"github.com/klausbreyer/pokerhans/internal/rating"
+)
+
+func TestRatingGame(t *testing.T) {
+	g := models.Game{
+		ID:      4,
+		Results: []models.GameResult{{PlayerID: 1, Position: 1}, {PlayerID: 2, Position: 2}},
+		Attendance: []models.Attendance{
+			{PlayerID: 1, Status: models.AttendanceAttended},
+			{PlayerID: 3, Status: models.AttendanceAttended},
+			{PlayerID: 4, Status: models.AttendanceAttended},
+			{PlayerID: 5, Status: models.AttendanceYes},
+		},
+	}
+
+	expected := []rating.Placing{
+		{PlayerID: 1, Rank: 1},
+		{PlayerID: 2, Rank: 2},
+		{PlayerID: 3, Rank: 3},
+		{PlayerID: 4, Rank: 3},
+	}
+	rg := ratingGame(g)
+	if len(rg.Placings) != len(expected) {
+		t.Fatalf("Expected %d placings, got %v", len(expected), rg.Placings)
+	}
+	for i, p := range expected {
+		if rg.Placings[i] != p {
+			t.Errorf("Placing %d: Expected %+v, got %+v", i, p, rg.Placings[i])
+		}
+	}
+
+	// Without a finishing order, nobody is ranked
+	g.Results = nil
+	if rg := ratingGame(g); len(rg.Placings) != 0 {
+		t.Errorf("Expected no placings without results, got %v", rg.Placings)
+	}
+}
+
+func TestRatingChart(t *testing.T) {
+	if chart := ratingChart([]rating.Point{{Rating: 1500}}); chart != "" {
+		t.Errorf("Expected no chart for a single point, got %q", chart)
+	}
+
+	chart := ratingChart([]rating.Point{{Rating: 1500}, {Rating: 1520}, {Rating: 1510}})
+	coords := strings.Fields(chart)
+	if len(coords) != 3 {
+		t.Fatalf("Expected 3 points, got %q", chart)
+	}
+	if coords[0] != "0.0,120.0" || coords[1] != "300.0,0.0" || coords[2] != "600.0,60.0" {
+		t.Errorf("Expected the lowest rating at the bottom and the highest at the top, got %q", chart)
+	}
+}
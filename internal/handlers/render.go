@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klausbreyer/pokerhans/internal/flash"
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+// errUnknownPage is returned by render when no template exists for a page
+var errUnknownPage = errors.New("unknown template page")
+
+// Page holds the data shared by every page rendered with the layout
+type Page struct {
+	CurrentYear int
+
+	// Flash is the message left by the previous request, shown once
+	Flash *flash.Message
+
+	// Group is the group the page belongs to, if any, and Base the prefix
+	// of its URLs, e.g. /g/hans. Links to pages of the group start with Base.
+	Group models.Group
+	Base  string
+
+	// Member is the logged-in member, or nil
+	Member *models.Member
+
+	// CSRFToken must be sent with every form, see the "csrf" template
+	CSRFToken string
+}
+
+// newPage returns the shared page data for the current request. It consumes
+// the pending flash message.
+func (h *Handler) newPage(w http.ResponseWriter, r *http.Request) Page {
+	return Page{
+		CurrentYear: time.Now().Year(),
+		Flash:       h.Flash.Pop(w, r),
+		Group:       h.Group,
+		Base:        h.path(""),
+		Member:      h.currentMember(r),
+		CSRFToken:   h.CSRF.Token(w, r, csrfSession(r)),
+	}
+}
+
+// loadTemplates parses every page template together with the layout.
+// Each page defines its own "content" block, so pages need separate
+// template sets instead of a single ParseGlob.
+func loadTemplates(logger *log.Logger) map[string]*template.Template {
+	templatesDir := filepath.Join("web", "templates")
+	layoutPath := filepath.Join(templatesDir, "layout.html")
+	logger.Printf("DEBUG: Loading templates from %s", templatesDir)
+
+	pages, err := filepath.Glob(filepath.Join(templatesDir, "*.html"))
+	if err != nil {
+		logger.Printf("ERROR: Failed to glob templates: %v", err)
+		// Continue with empty list if glob fails
+		pages = []string{}
+	}
+
+	templates := make(map[string]*template.Template)
+	for _, page := range pages {
+		if page == layoutPath {
+			continue
+		}
+
+		name := strings.TrimSuffix(filepath.Base(page), ".html")
+		logger.Printf("DEBUG: Found template file: %s (page %q)", page, name)
+
+		// Use Must to panic on errors
+		templates[name] = template.Must(template.ParseFiles(layoutPath, page))
+	}
+
+	return templates
+}
+
+// render executes the layout with the content of the given page and writes
+// the result. On failure it responds with a 500 and returns the error.
+func (h *Handler) render(w http.ResponseWriter, page string, data interface{}) error {
+	return h.renderStatus(w, http.StatusOK, page, data)
+}
+
+// renderStatus is like render but writes the given status code
+func (h *Handler) renderStatus(w http.ResponseWriter, status int, page string, data interface{}) error {
+	tmpl, ok := h.Templates[page]
+	if !ok {
+		h.Logger.Printf("ERROR: Unknown template page: %s", page)
+		http.Error(w, "Failed to render template", http.StatusInternalServerError)
+		return errUnknownPage
+	}
+
+	// Capture the template output to inspect it
+	var buf bytes.Buffer
+	err := tmpl.ExecuteTemplate(&buf, "layout", data)
+	if err != nil {
+		h.Logger.Printf("ERROR: Template rendering failed: %v", err)
+		http.Error(w, "Failed to render template", http.StatusInternalServerError)
+		return err
+	}
+
+	output := buf.String()
+	h.Logger.Printf("TEMPLATE_OUTPUT_LENGTH: %d bytes (page %s)", len(output), page)
+	if len(output) == 0 {
+		h.Logger.Printf("ERROR: Template output is empty!")
+	}
+
+	// Set Content-Type header
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	// Write the output to the response
+	_, err = w.Write(buf.Bytes())
+	if err != nil {
+		h.Logger.Printf("ERROR: Failed to write response: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+// parseFinishingOrder reads the submitted places of a game form. Each row
+// posts a result_player_id together with a result_position; rows without a
+// position belong to players who did not take part. The returned player IDs
+// are ordered from winner to first player out.
+func parseFinishingOrder(r *http.Request) ([]int, error) {
+	playerIDs := r.Form["result_player_id"]
+	positions := r.Form["result_position"]
+	if len(playerIDs) != len(positions) {
+		return nil, fmt.Errorf("mismatched player and place fields")
+	}
+
+	type place struct {
+		playerID int
+		position int
+	}
+
+	var places []place
+	seenPlayers := make(map[int]bool)
+	seenPositions := make(map[int]bool)
+	for i := range playerIDs {
+		value := strings.TrimSpace(positions[i])
+		if value == "" {
+			continue
+		}
+
+		playerID, err := strconv.Atoi(playerIDs[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid player ID %q", playerIDs[i])
+		}
+
+		position, err := strconv.Atoi(value)
+		if err != nil || position < 1 {
+			return nil, fmt.Errorf("invalid place %q", value)
+		}
+
+		if seenPlayers[playerID] {
+			return nil, fmt.Errorf("player %d was ranked twice", playerID)
+		}
+		if seenPositions[position] {
+			return nil, fmt.Errorf("two players share place %d", position)
+		}
+		seenPlayers[playerID] = true
+		seenPositions[position] = true
+
+		places = append(places, place{playerID: playerID, position: position})
+	}
+
+	// Gaps in the entered places are closed, so 1, 2, 4 becomes 1, 2, 3
+	sort.Slice(places, func(i, j int) bool {
+		return places[i].position < places[j].position
+	})
+
+	finishingOrder := make([]int, len(places))
+	for i, p := range places {
+		finishingOrder[i] = p.playerID
+	}
+
+	return finishingOrder, nil
+}
+
+// parseLedger reads the submitted buy-ins, rebuys and payouts of a game form.
+// They are posted per row alongside result_player_id; rows without any amount
+// are skipped.
+func parseLedger(r *http.Request) ([]models.LedgerEntry, error) {
+	playerIDs := r.Form["result_player_id"]
+	buyIns := r.Form["buy_in"]
+	rebuys := r.Form["rebuys"]
+	payouts := r.Form["payout"]
+	if len(buyIns) == 0 && len(rebuys) == 0 && len(payouts) == 0 {
+		return nil, nil
+	}
+	if len(buyIns) != len(playerIDs) || len(rebuys) != len(playerIDs) || len(payouts) != len(playerIDs) {
+		return nil, fmt.Errorf("mismatched player and amount fields")
+	}
+
+	var entries []models.LedgerEntry
+	for i := range playerIDs {
+		if strings.TrimSpace(buyIns[i]+rebuys[i]+payouts[i]) == "" {
+			continue
+		}
+
+		playerID, err := strconv.Atoi(playerIDs[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid player ID %q", playerIDs[i])
+		}
+
+		entry := models.LedgerEntry{PlayerID: playerID}
+		for _, amount := range []struct {
+			value string
+			dest  *models.Cents
+		}{
+			{buyIns[i], &entry.BuyIn},
+			{rebuys[i], &entry.Rebuys},
+			{payouts[i], &entry.Payout},
+		} {
+			c, err := models.ParseCents(amount.value)
+			if err != nil {
+				return nil, err
+			}
+			if c < 0 {
+				return nil, fmt.Errorf("amounts cannot be negative")
+			}
+			*amount.dest = c
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+func resultsRequest(playerIDs, positions []string) *http.Request {
+	return &http.Request{Form: url.Values{
+		"result_player_id": playerIDs,
+		"result_position":  positions,
+	}}
+}
+
+func TestParseFinishingOrder(t *testing.T) {
+	r := resultsRequest(
+		[]string{"1", "2", "3", "4"},
+		[]string{"2", "", "1", "5"},
+	)
+
+	order, err := parseFinishingOrder(r)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []int{3, 1, 4}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("Expected finishing order %v, got %v", want, order)
+	}
+}
+
+func TestParseFinishingOrderEmpty(t *testing.T) {
+	order, err := parseFinishingOrder(resultsRequest([]string{"1", "2"}, []string{"", " "}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(order) != 0 {
+		t.Errorf("Expected no results, got %v", order)
+	}
+}
+
+func TestParseFinishingOrderErrors(t *testing.T) {
+	cases := []struct {
+		name      string
+		playerIDs []string
+		positions []string
+	}{
+		{"shared place", []string{"1", "2"}, []string{"1", "1"}},
+		{"ranked twice", []string{"1", "1"}, []string{"1", "2"}},
+		{"invalid place", []string{"1"}, []string{"first"}},
+		{"zero place", []string{"1"}, []string{"0"}},
+		{"mismatched fields", []string{"1", "2"}, []string{"1"}},
+	}
+
+	for _, c := range cases {
+		if _, err := parseFinishingOrder(resultsRequest(c.playerIDs, c.positions)); err == nil {
+			t.Errorf("%s: expected an error", c.name)
+		}
+	}
+}
+
+func TestParseLedger(t *testing.T) {
+	r := &http.Request{Form: url.Values{
+		"result_player_id": {"1", "2", "3"},
+		"buy_in":           {"10", "", "10"},
+		"rebuys":           {"5,50", "", ""},
+		"payout":           {"", "", "25.50"},
+	}}
+
+	entries, err := parseLedger(r)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []models.LedgerEntry{
+		{PlayerID: 1, BuyIn: 1000, Rebuys: 550},
+		{PlayerID: 3, BuyIn: 1000, Payout: 2550},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Expected ledger %v, got %v", want, entries)
+	}
+}
+
+func TestParseLedgerErrors(t *testing.T) {
+	cases := map[string]url.Values{
+		"negative amount": {"result_player_id": {"1"}, "buy_in": {"-10"}, "rebuys": {""}, "payout": {""}},
+		"invalid amount":  {"result_player_id": {"1"}, "buy_in": {"ten"}, "rebuys": {""}, "payout": {""}},
+		"missing fields":  {"result_player_id": {"1", "2"}, "buy_in": {"10"}, "rebuys": {""}, "payout": {""}},
+	}
+
+	for name, form := range cases {
+		if _, err := parseLedger(&http.Request{Form: form}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
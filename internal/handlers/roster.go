@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/klausbreyer/pokerhans/internal/flash"
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+// RosterEntry is a player in the roster editor together with their membership
+type RosterEntry struct {
+	models.Player
+	OnRoster bool
+}
+
+// RosterHandler shows the roster editor of a season
+func (h *Handler) RosterHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: RosterHandler - Processing roster view")
+
+	if !h.authorizeManage(w, r) {
+		return
+	}
+
+	// Extract season ID from URL
+	path := r.URL.Path
+	re := regexp.MustCompile(`^/season/(\d+)/roster$`)
+	matches := re.FindStringSubmatch(path)
+
+	if len(matches) < 2 {
+		h.Logger.Printf("ERROR: Invalid roster URL: %s", path)
+		http.NotFound(w, r)
+		return
+	}
+
+	seasonID, err := strconv.Atoi(matches[1])
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid season ID: %s", matches[1])
+		http.Error(w, "Invalid season ID", http.StatusBadRequest)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Season ID = %d", seasonID)
+
+	season, err := h.Repo.GetSeason(seasonID)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: Season %d not found", seasonID)
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting season failed: %v", err)
+		http.Error(w, "Failed to load season", http.StatusInternalServerError)
+		return
+	}
+
+	roster, err := h.Repo.GetSeasonRoster(seasonID)
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting roster failed: %v", err)
+		http.Error(w, "Failed to load roster", http.StatusInternalServerError)
+		return
+	}
+	h.Logger.Printf("DATA: Found %d players on roster of season %d", len(roster), seasonID)
+
+	players, err := h.Repo.GetAllPlayers()
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting players failed: %v", err)
+		http.Error(w, "Failed to load players", http.StatusInternalServerError)
+		return
+	}
+
+	onRoster := make(map[int]bool, len(roster))
+	for _, p := range roster {
+		onRoster[p.ID] = true
+	}
+
+	// Offer every active player, plus inactive players still on the roster
+	var entries []RosterEntry
+	for _, p := range players {
+		if p.Active || onRoster[p.ID] {
+			entries = append(entries, RosterEntry{Player: p, OnRoster: onRoster[p.ID]})
+		}
+	}
+
+	data := struct {
+		Page
+		Season  models.Season
+		Entries []RosterEntry
+		Count   int
+	}{
+		Page:    h.newPage(w, r),
+		Season:  season,
+		Entries: entries,
+		Count:   len(roster),
+	}
+
+	h.Logger.Printf("RENDER: Rendering layout template with roster content")
+	h.render(w, "roster", data)
+}
+
+// UpdateRosterHandler handles saving the roster of a season
+func (h *Handler) UpdateRosterHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: UpdateRosterHandler - Processing roster update")
+
+	if !h.authorizeManage(w, r) {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	seasonID, ok := h.editableSeasonID(w, r)
+	if !ok {
+		return
+	}
+
+	var playerIDs []int
+	for _, v := range r.Form["player_id"] {
+		playerID, err := strconv.Atoi(v)
+		if err != nil {
+			h.Logger.Printf("ERROR: Invalid player_id: %s", v)
+			http.Error(w, "Invalid player ID", http.StatusBadRequest)
+			return
+		}
+		playerIDs = append(playerIDs, playerID)
+	}
+
+	h.Logger.Printf("PARAM: Season ID = %d, Players = %v", seasonID, playerIDs)
+
+	err := h.Repo.SetSeasonRoster(seasonID, playerIDs)
+	if errors.Is(err, models.ErrUnknownPlayer) {
+		h.Logger.Printf("ERROR: Roster lists an unknown player")
+		http.Error(w, "Unknown player", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Saving roster in database: %v", err)
+		http.Error(w, "Failed to save roster", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Roster saved successfully")
+	h.Flash.Set(w, flash.LevelSuccess, "Roster saved")
+
+	// Redirect back to season page
+	redirectURL := h.path("/season/" + strconv.Itoa(seasonID))
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// CopyRosterHandler handles copying the previous season's roster
+func (h *Handler) CopyRosterHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: CopyRosterHandler - Processing roster copy")
+
+	if !h.authorizeManage(w, r) {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	seasonID, ok := h.editableSeasonID(w, r)
+	if !ok {
+		return
+	}
+
+	h.Logger.Printf("PARAM: Season ID = %d", seasonID)
+
+	err := h.Repo.CopyPreviousRoster(seasonID)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: No season before season %d", seasonID)
+		h.Flash.Set(w, flash.LevelError, "There is no previous season to copy from")
+		http.Redirect(w, r, h.path("/season/"+strconv.Itoa(seasonID)+"/roster"), http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Copying roster in database: %v", err)
+		http.Error(w, "Failed to copy roster", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Roster copied successfully")
+	h.Flash.Set(w, flash.LevelSuccess, "Roster copied from the previous season")
+
+	redirectURL := h.path("/season/" + strconv.Itoa(seasonID) + "/roster")
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// editableSeasonID reads the season_id form value and makes sure the season
+// exists and is not archived. It writes an error response and returns false
+// otherwise.
+func (h *Handler) editableSeasonID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	seasonID, err := strconv.Atoi(r.FormValue("season_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid season_id: %s", r.FormValue("season_id"))
+		http.Error(w, "Invalid season ID", http.StatusBadRequest)
+		return 0, false
+	}
+
+	season, err := h.Repo.GetSeason(seasonID)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: Season %d not found", seasonID)
+		http.Error(w, "Season not found", http.StatusNotFound)
+		return 0, false
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting season failed: %v", err)
+		http.Error(w, "Failed to load season", http.StatusInternalServerError)
+		return 0, false
+	}
+
+	if season.IsArchived() {
+		h.Logger.Printf("ERROR: Season %d is archived", seasonID)
+		http.Error(w, "Season is archived", http.StatusConflict)
+		return 0, false
+	}
+
+	return seasonID, true
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klausbreyer/pokerhans/internal/flash"
+	"github.com/klausbreyer/pokerhans/internal/models"
+	"github.com/klausbreyer/pokerhans/internal/schedule"
+)
+
+// GenerateScheduleHandler handles drawing a host order and dates for the
+// players still to visit and saving them as planned games
+func (h *Handler) GenerateScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: GenerateScheduleHandler - Processing schedule generation")
+
+	if !h.authorizeManage(w, r) {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	// Parse form values
+	seasonID, err := strconv.Atoi(r.FormValue("season_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid season_id: %s", r.FormValue("season_id"))
+		http.Error(w, "Invalid season ID", http.StatusBadRequest)
+		return
+	}
+
+	// Invalid input is shown next to the fields of the reopened form
+	form := &GameForm{
+		Modal:  "scheduleModal",
+		Values: r.PostForm,
+		Errors: FieldErrors{},
+	}
+
+	start, err := time.Parse("2006-01-02", r.FormValue("start_date"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid start_date format: %s", r.FormValue("start_date"))
+		form.Errors["start_date"] = "Invalid date format"
+	}
+
+	weekday, err := strconv.Atoi(r.FormValue("weekday"))
+	if err != nil || weekday < 0 || weekday > 6 {
+		h.Logger.Printf("ERROR: Invalid weekday: %s", r.FormValue("weekday"))
+		form.Errors["weekday"] = "Choose a day of the week"
+	}
+
+	interval, err := strconv.Atoi(r.FormValue("interval_weeks"))
+	if err != nil || interval < 1 {
+		h.Logger.Printf("ERROR: Invalid interval_weeks: %s", r.FormValue("interval_weeks"))
+		form.Errors["interval_weeks"] = "Enter a number of weeks of at least 1"
+	}
+
+	blackouts, err := parseBlackouts(r.FormValue("blackouts"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid blackouts: %v", err)
+		form.Errors["blackouts"] = err.Error()
+	}
+
+	if len(form.Errors) > 0 {
+		h.renderGameForm(w, r, http.StatusBadRequest, seasonID, form)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Season ID = %d, Start = %s, Weekday = %s, Interval = %d weeks, Blackouts = %d",
+		seasonID, start.Format("2006-01-02"), time.Weekday(weekday), interval, len(blackouts))
+
+	season, err := h.Repo.GetSeason(seasonID)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: Season %d not found", seasonID)
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting season failed: %v", err)
+		http.Error(w, "Failed to load season", http.StatusInternalServerError)
+		return
+	}
+
+	// Everyone who has not hosted yet gets a new slot
+	players, err := h.Repo.GetSeasonPlayers(seasonID)
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting players failed: %v", err)
+		http.Error(w, "Failed to load players", http.StatusInternalServerError)
+		return
+	}
+
+	var hosts []schedule.Host
+	for _, p := range players {
+		if !p.HasHosted {
+			hosts = append(hosts, schedule.Host{ID: p.ID, Name: p.Name})
+		}
+	}
+
+	history, err := h.Repo.GetPastHostPositions(seasonID)
+	if err != nil {
+		h.Logger.Printf("ERROR: Getting host history failed: %v", err)
+		http.Error(w, "Failed to load host history", http.StatusInternalServerError)
+		return
+	}
+
+	cadence := schedule.Cadence{
+		Start:         start,
+		Weekday:       time.Weekday(weekday),
+		IntervalWeeks: interval,
+		Blackouts:     blackouts,
+	}
+	// No dates after the season ends, as they could not be saved
+	if season.EndDate != nil {
+		cadence.End = *season.EndDate
+	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	slots := schedule.Generate(hosts, history, cadence, rng)
+
+	planned := make([]models.PlannedGame, len(slots))
+	for i, s := range slots {
+		h.Logger.Printf("DATA: Slot %d: %s on %s", i+1, s.Host.Name, s.Date.Format("2006-01-02"))
+		planned[i] = models.PlannedGame{HostID: s.Host.ID, GameDate: s.Date}
+	}
+
+	cancelled, err := h.Repo.SaveSchedule(seasonID, planned)
+	if err != nil {
+		h.Logger.Printf("ERROR: Saving schedule in database: %v", err)
+		status, message := gameErrorResponse(err, "Failed to save schedule")
+		if status == http.StatusNotFound || status == http.StatusInternalServerError {
+			http.Error(w, message, status)
+			return
+		}
+		form.Errors["form"] = message
+		h.renderGameForm(w, r, status, seasonID, form)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Schedule with %d games saved, %d games cancelled", len(planned), cancelled)
+	level, message := flash.LevelSuccess, fmt.Sprintf("Planned %d games", len(planned))
+	if cancelled > 0 {
+		message += fmt.Sprintf(" and cancelled %d games of hosts no longer to visit", cancelled)
+	}
+	if left := len(hosts) - len(planned); left > 0 {
+		level = flash.LevelWarning
+		message += fmt.Sprintf(". %d hosts did not fit before the season ends", left)
+	}
+	h.Flash.Set(w, level, message)
+
+	// Redirect back to season page
+	redirectURL := h.path("/season/" + strconv.Itoa(seasonID))
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// parseBlackouts reads dates in the format 2006-01-02, separated by commas,
+// spaces or line breaks
+func parseBlackouts(value string) ([]time.Time, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\n' || r == '\r' || r == '\t'
+	})
+
+	var dates []time.Time
+	for _, f := range fields {
+		d, err := time.Parse("2006-01-02", f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blackout date %q, use YYYY-MM-DD", f)
+		}
+		dates = append(dates, d)
+	}
+	return dates, nil
+}
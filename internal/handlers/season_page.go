@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/klausbreyer/pokerhans/internal/models"
+	"github.com/klausbreyer/pokerhans/internal/settlement"
+	"github.com/klausbreyer/pokerhans/internal/suggest"
+)
+
+// SeasonPage is the data of the season template
+type SeasonPage struct {
+	Page
+	Seasons        []models.Season
+	CurrentSeason  models.Season
+	VisitedPlayers []models.PlayerStatus
+	ToVisitPlayers []models.PlayerStatus
+	Games          []models.Game
+	Upcoming       []models.Game
+	Standings      []models.Standing
+	Points         models.PointsScheme
+	Balances       []models.PlayerBalance
+	Transfers      []settlement.Transfer
+	GameTransfers  map[int][]settlement.Transfer
+	Payments       []models.SettlementPayment
+	AllPlayers     []models.Player
+	CurrentDate    string
+	IsEditable     bool
+
+	// Suggestions ranks who could host next, best first
+	Suggestions []suggest.Suggestion
+
+	// Form is set when a submitted form failed and is shown again
+	Form *GameForm
+
+	// RSVPPlayers lists the players the visitor may answer for, see
+	// setRSVPPlayers
+	RSVPPlayers []models.PlayerStatus
+}
+
+// loadSeasonPage collects everything shown on the page of a season. The
+// shared Page data is left for the caller to fill in.
+func (h *Handler) loadSeasonPage(seasonID int) (*SeasonPage, error) {
+	// Get all seasons for the dropdown
+	seasons, err := h.Repo.GetSeasons()
+	if err != nil {
+		return nil, err
+	}
+	h.Logger.Printf("DATA: Found %d seasons total", len(seasons))
+
+	// Get players for this season
+	players, err := h.Repo.GetSeasonPlayers(seasonID)
+	if err != nil {
+		return nil, err
+	}
+	h.Logger.Printf("DATA: Found %d players for season %d", len(players), seasonID)
+
+	// Get games for this season
+	games, err := h.Repo.GetGames(seasonID)
+	if err != nil {
+		return nil, err
+	}
+	h.Logger.Printf("DATA: Found %d games for season %d", len(games), seasonID)
+
+	// Get standings for this season
+	standings, err := h.Repo.GetSeasonStandings(seasonID, h.Points)
+	if err != nil {
+		return nil, err
+	}
+	h.Logger.Printf("DATA: Found %d ranked players for season %d", len(standings), seasonID)
+
+	// Get net profits for this season
+	balances, err := h.Repo.GetSeasonBalances(seasonID)
+	if err != nil {
+		return nil, err
+	}
+	h.Logger.Printf("DATA: Found %d player balances for season %d", len(balances), seasonID)
+
+	// Work out who still has to pay whom, taking settled transfers into account
+	payments, err := h.Repo.GetSettlementPayments(seasonID)
+	if err != nil {
+		return nil, err
+	}
+	transfers := settlement.Settle(settlement.ApplyPayments(balances, payments))
+	gameTransfers := openGameTransfers(games, payments)
+	h.Logger.Printf("DATA: %d settled payments, %d open transfers for season %d", len(payments), len(transfers), seasonID)
+
+	// Reverse the order of games to show oldest first
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].GameDate.Before(games[j].GameDate)
+	})
+
+	// Planned and postponed games are shown apart from the played ones
+	upcoming := models.UpcomingGames(games)
+	games = models.PlayedGames(games)
+	h.Logger.Printf("DATA: %d games played, %d upcoming", len(games), len(upcoming))
+
+	// Separate players into visited and not visited
+	var visited, notVisited []models.PlayerStatus
+	for _, p := range players {
+		if p.HasHosted {
+			visited = append(visited, p)
+		} else {
+			notVisited = append(notVisited, p)
+		}
+	}
+
+	// Sort visited players by game date (oldest first)
+	sort.Slice(visited, func(i, j int) bool {
+		return visited[i].GameDate.Before(visited[j].GameDate)
+	})
+
+	// Sort players to visit by created_at date (oldest first)
+	sort.Slice(notVisited, func(i, j int) bool {
+		return notVisited[i].CreatedAt.Before(notVisited[j].CreatedAt)
+	})
+	h.Logger.Printf("DATA: %d players visited, %d players to visit", len(visited), len(notVisited))
+
+	// Get active players for the dropdowns
+	allPlayers, err := h.Repo.GetActivePlayers()
+	if err != nil {
+		return nil, err
+	}
+	h.Logger.Printf("DATA: Found %d active players in system", len(allPlayers))
+
+	// Current season
+	var currentSeason models.Season
+	for _, s := range seasons {
+		if s.ID == seasonID {
+			currentSeason = s
+			break
+		}
+	}
+	h.Logger.Printf("DATA: Current season name: %s", currentSeason.Name)
+
+	// Only active seasons can be edited; archived seasons are read-only
+	isEditable := currentSeason.ID != 0 && !currentSeason.IsArchived()
+
+	// Suggest who could host next, based on hosting across all seasons
+	var suggestions []suggest.Suggestion
+	if isEditable {
+		hostings, err := h.Repo.GetHostings()
+		if err != nil {
+			return nil, err
+		}
+		suggestions = suggest.Rank(hostCandidates(notVisited, hostings, seasons, seasonID), h.Suggest, time.Now())
+		h.Logger.Printf("DATA: %d host suggestions", len(suggestions))
+	}
+
+	return &SeasonPage{
+		Seasons:        seasons,
+		CurrentSeason:  currentSeason,
+		VisitedPlayers: visited,
+		ToVisitPlayers: notVisited,
+		Games:          games,
+		Upcoming:       upcoming,
+		Standings:      standings,
+		Points:         h.Points,
+		Balances:       balances,
+		Transfers:      transfers,
+		GameTransfers:  gameTransfers,
+		Payments:       payments,
+		AllPlayers:     allPlayers,
+		CurrentDate:    time.Now().Format("2006-01-02"),
+		IsEditable:     isEditable,
+		Suggestions:    suggestions,
+	}, nil
+}
+
+// setRSVPPlayers fills in who the visitor of the page may answer for: every
+// player on the roster for admins, and for visitors who are not logged in yet
+// and will be asked to, but only their own player for other members
+func (h *Handler) setRSVPPlayers(p *SeasonPage) {
+	roster := append(append([]models.PlayerStatus{}, p.ToVisitPlayers...), p.VisitedPlayers...)
+	if p.Member == nil {
+		p.RSVPPlayers = roster
+		return
+	}
+
+	role, err := h.Repo.GetGroupRole(p.Member.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: Getting group role failed: %v", err)
+	}
+	if role.CanManage() {
+		p.RSVPPlayers = roster
+		return
+	}
+
+	ownPlayerID, err := h.Repo.GetMemberPlayer(p.Member.ID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			h.Logger.Printf("ERROR: Getting member's player failed: %v", err)
+		}
+		return
+	}
+	for _, player := range roster {
+		if player.ID == ownPlayerID {
+			p.RSVPPlayers = append(p.RSVPPlayers, player)
+		}
+	}
+}
+
+// ParticipantPool lists the players offered in the participant rows of the
+// add game form. Others is only set if some players are expected.
+type ParticipantPool struct {
+	Expected []models.Player
+	Others   []models.Player
+}
+
+// Pool returns the players offered when adding the game of a host. If the
+// host has an upcoming game, the players who answered yes or maybe come
+// first and the rest are tucked away.
+func (p *SeasonPage) Pool(hostID int) ParticipantPool {
+	for _, g := range p.Upcoming {
+		if g.HostID == hostID {
+			expected, others := models.ParticipantPool(g, p.AllPlayers)
+			return ParticipantPool{Expected: expected, Others: others}
+		}
+	}
+	return ParticipantPool{Expected: p.AllPlayers}
+}
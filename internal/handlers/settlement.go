@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/klausbreyer/pokerhans/internal/flash"
+	"github.com/klausbreyer/pokerhans/internal/models"
+	"github.com/klausbreyer/pokerhans/internal/settlement"
+)
+
+// openGameTransfers returns the transfers still needed to settle each game
+// with a ledger, keyed by game ID. Only payments made for that game count.
+func openGameTransfers(games []models.Game, payments []models.SettlementPayment) map[int][]settlement.Transfer {
+	paymentsByGame := make(map[int][]models.SettlementPayment)
+	for _, p := range payments {
+		if p.GameID != nil {
+			paymentsByGame[*p.GameID] = append(paymentsByGame[*p.GameID], p)
+		}
+	}
+
+	transfers := make(map[int][]settlement.Transfer)
+	for _, g := range games {
+		if len(g.Ledger) == 0 {
+			continue
+		}
+		transfers[g.ID] = settlement.Settle(settlement.ApplyPayments(g.Balances(), paymentsByGame[g.ID]))
+	}
+	return transfers
+}
+
+// SettleTransferHandler handles marking a transfer as settled
+func (h *Handler) SettleTransferHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: SettleTransferHandler - Processing settled transfer")
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	// Parse form values
+	seasonID, err := strconv.Atoi(r.FormValue("season_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid season_id: %s", r.FormValue("season_id"))
+		http.Error(w, "Invalid season ID", http.StatusBadRequest)
+		return
+	}
+
+	// Handle game_id (optional, set when settling a single game)
+	var gameID *int
+	if gameIDStr := r.FormValue("game_id"); gameIDStr != "" {
+		gameVal, err := strconv.Atoi(gameIDStr)
+		if err != nil {
+			h.Logger.Printf("ERROR: Invalid game_id: %s", gameIDStr)
+			http.Error(w, "Invalid game ID", http.StatusBadRequest)
+			return
+		}
+		gameID = &gameVal
+	}
+
+	fromID, err := strconv.Atoi(r.FormValue("from_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid from_id: %s", r.FormValue("from_id"))
+		http.Error(w, "Invalid payer", http.StatusBadRequest)
+		return
+	}
+
+	toID, err := strconv.Atoi(r.FormValue("to_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid to_id: %s", r.FormValue("to_id"))
+		http.Error(w, "Invalid receiver", http.StatusBadRequest)
+		return
+	}
+
+	amount, err := strconv.ParseInt(r.FormValue("amount_cents"), 10, 64)
+	if err != nil || amount <= 0 || fromID == toID {
+		h.Logger.Printf("ERROR: Invalid transfer: %d -> %d, %s", fromID, toID, r.FormValue("amount_cents"))
+		http.Error(w, "Invalid transfer", http.StatusBadRequest)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Season ID = %d, From = %d, To = %d, Amount = %d", seasonID, fromID, toID, amount)
+
+	if _, err := h.Repo.GetSeason(seasonID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.Logger.Printf("ERROR: Season %d not found", seasonID)
+			http.Error(w, "Season not found", http.StatusNotFound)
+			return
+		}
+		h.Logger.Printf("ERROR: Getting season failed: %v", err)
+		http.Error(w, "Failed to load season", http.StatusInternalServerError)
+		return
+	}
+
+	if !h.authorizeSeason(w, r, seasonID) {
+		return
+	}
+
+	err = h.Repo.AddSettlementPayment(seasonID, gameID, fromID, toID, models.Cents(amount))
+	if err != nil {
+		h.Logger.Printf("ERROR: Adding settlement payment to database: %v", err)
+		status, message := gameErrorResponse(err, "Failed to mark transfer as settled")
+		http.Error(w, message, status)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Transfer marked as settled")
+	h.Flash.Set(w, flash.LevelSuccess, "Transfer marked as settled")
+
+	// Redirect back to season page
+	redirectURL := h.path("/season/" + strconv.Itoa(seasonID))
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"github.com/klausbreyer/pokerhans/internal/models"
+	"github.com/klausbreyer/pokerhans/internal/suggest"
+)
+
+// hostCandidates describes the players who could host next: those still to
+// visit without an upcoming game. hostings are all played games of all
+// seasons, oldest first.
+func hostCandidates(toVisit []models.PlayerStatus, hostings []models.Hosting, seasons []models.Season, seasonID int) []suggest.Candidate {
+	// The previous season is the newest one created before this one
+	previous := 0
+	for _, s := range seasons {
+		if s.ID < seasonID && s.ID > previous {
+			previous = s.ID
+		}
+	}
+
+	// Hostings are oldest first, so the last one of a host or season wins
+	last := make(map[int]models.Hosting)
+	count := make(map[int]int)
+	var final models.Hosting
+	for _, h := range hostings {
+		last[h.HostID] = h
+		count[h.HostID]++
+		if h.SeasonID == previous {
+			final = h
+		}
+	}
+
+	var candidates []suggest.Candidate
+	for _, p := range toVisit {
+		if p.HasUpcomingGame() {
+			continue
+		}
+
+		c := suggest.Candidate{
+			ID:          p.ID,
+			Name:        p.Name,
+			Joined:      p.CreatedAt,
+			TimesHosted: count[p.ID],
+		}
+		if h, ok := last[p.ID]; ok {
+			c.LastHosted = h.GameDate
+			c.LastSeason = h.SeasonName
+		}
+		if final.SeasonID != 0 && final.HostID == p.ID {
+			c.HostedLastFinal = true
+			c.FinalSeason = final.SeasonName
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates
+}
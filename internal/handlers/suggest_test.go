@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+func TestHostCandidates(t *testing.T) {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	seasons := []models.Season{{ID: 3, Name: "Fall 2025"}, {ID: 2, Name: "Summer 2025"}, {ID: 1, Name: "Spring 2025"}}
+	hostings := []models.Hosting{
+		{HostID: 1, SeasonID: 1, SeasonName: "Spring 2025", GameDate: date(3, 7)},
+		{HostID: 2, SeasonID: 1, SeasonName: "Spring 2025", GameDate: date(3, 21)},
+		{HostID: 1, SeasonID: 2, SeasonName: "Summer 2025", GameDate: date(6, 6)},
+		{HostID: 2, SeasonID: 2, SeasonName: "Summer 2025", GameDate: date(8, 29)},
+	}
+	toVisit := []models.PlayerStatus{
+		{Player: models.Player{ID: 1, Name: "Anna"}},
+		{Player: models.Player{ID: 2, Name: "Max"}},
+		{Player: models.Player{ID: 3, Name: "Jonas"}},
+		{Player: models.Player{ID: 4, Name: "Lisa"}, GameStatus: models.GameStatusPlanned},
+	}
+
+	candidates := hostCandidates(toVisit, hostings, seasons, 3)
+	if len(candidates) != 3 {
+		t.Fatalf("Expected 3 candidates without the planned host, got %d", len(candidates))
+	}
+
+	anna, maxi, jonas := candidates[0], candidates[1], candidates[2]
+	if anna.TimesHosted != 2 || !anna.LastHosted.Equal(date(6, 6)) || anna.LastSeason != "Summer 2025" || anna.HostedLastFinal {
+		t.Errorf("Unexpected candidate for Anna: %+v", anna)
+	}
+	if !maxi.HostedLastFinal || maxi.FinalSeason != "Summer 2025" {
+		t.Errorf("Expected Max to have hosted the final of Summer 2025, got %+v", maxi)
+	}
+	if jonas.TimesHosted != 0 || !jonas.LastHosted.IsZero() {
+		t.Errorf("Expected Jonas to have never hosted, got %+v", jonas)
+	}
+}
@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/klausbreyer/pokerhans/internal/flash"
+)
+
+// PlanGameHandler handles putting in a game that has not been played yet
+func (h *Handler) PlanGameHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: PlanGameHandler - Processing game planning")
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	// Parse form values
+	seasonID, err := strconv.Atoi(r.FormValue("season_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid season_id: %s", r.FormValue("season_id"))
+		http.Error(w, "Invalid season ID", http.StatusBadRequest)
+		return
+	}
+
+	if !h.authorizeSeason(w, r, seasonID) {
+		return
+	}
+
+	hostID, err := strconv.Atoi(r.FormValue("host_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid host_id: %s", r.FormValue("host_id"))
+		http.Error(w, "Invalid host ID", http.StatusBadRequest)
+		return
+	}
+
+	// Invalid input is shown next to the field of the reopened form
+	form := &GameForm{
+		Modal:  fmt.Sprintf("planGameModal-%d", hostID),
+		Values: r.PostForm,
+	}
+
+	gameDate, err := time.Parse("2006-01-02", r.FormValue("game_date"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid game_date format: %s", r.FormValue("game_date"))
+		form.Errors = FieldErrors{"game_date": "Invalid date format"}
+		h.renderGameForm(w, r, http.StatusBadRequest, seasonID, form)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Season ID = %d, Host ID = %d, Game Date = %s",
+		seasonID, hostID, gameDate.Format("2006-01-02"))
+
+	gameID, err := h.Repo.PlanGame(seasonID, hostID, gameDate)
+	if err != nil {
+		h.Logger.Printf("ERROR: Planning game in database: %v", err)
+		status, message := gameErrorResponse(err, "Failed to plan game")
+		if status == http.StatusNotFound || status == http.StatusInternalServerError {
+			http.Error(w, message, status)
+			return
+		}
+		form.Errors = FieldErrors{"form": message}
+		h.renderGameForm(w, r, status, seasonID, form)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Game %d planned successfully", gameID)
+	h.Flash.Set(w, flash.LevelSuccess, "Game planned for "+gameDate.Format("Jan 02, 2006"))
+
+	// Redirect back to season page
+	redirectURL := h.path("/season/" + strconv.Itoa(seasonID))
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// PostponeGameHandler handles moving an upcoming game to a new date
+func (h *Handler) PostponeGameHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: PostponeGameHandler - Processing game postponement")
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	// Parse form values
+	gameID, err := strconv.Atoi(r.FormValue("game_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid game_id: %s", r.FormValue("game_id"))
+		http.Error(w, "Invalid game ID", http.StatusBadRequest)
+		return
+	}
+
+	// The season is the game's own, whatever page the form was sent from
+	game, ok := h.authorizeGame(w, r, gameID)
+	if !ok {
+		return
+	}
+	seasonID := game.SeasonID
+
+	// Invalid input is shown next to the field of the reopened form
+	form := &GameForm{
+		Modal:  fmt.Sprintf("postponeGameModal-%d", gameID),
+		Values: r.PostForm,
+	}
+
+	newDate, err := time.Parse("2006-01-02", r.FormValue("new_date"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid new_date format: %s", r.FormValue("new_date"))
+		form.Errors = FieldErrors{"new_date": "Invalid date format"}
+		h.renderGameForm(w, r, http.StatusBadRequest, seasonID, form)
+		return
+	}
+
+	h.Logger.Printf("PARAM: Game ID = %d, Season ID = %d, New Date = %s",
+		gameID, seasonID, newDate.Format("2006-01-02"))
+
+	err = h.Repo.PostponeGame(gameID, newDate)
+	if err != nil {
+		h.Logger.Printf("ERROR: Postponing game in database: %v", err)
+		status, message := gameErrorResponse(err, "Failed to postpone game")
+		if status == http.StatusNotFound || status == http.StatusInternalServerError {
+			http.Error(w, message, status)
+			return
+		}
+		form.Errors = FieldErrors{"form": message}
+		h.renderGameForm(w, r, status, seasonID, form)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Game postponed successfully")
+	h.Flash.Set(w, flash.LevelSuccess, "Game postponed to "+newDate.Format("Jan 02, 2006"))
+
+	// Redirect back to season page
+	redirectURL := h.path("/season/" + strconv.Itoa(seasonID))
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// CancelGameHandler handles cancelling an upcoming game
+func (h *Handler) CancelGameHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: CancelGameHandler - Processing game cancellation")
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	// Parse form values
+	gameID, err := strconv.Atoi(r.FormValue("game_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid game_id: %s", r.FormValue("game_id"))
+		http.Error(w, "Invalid game ID", http.StatusBadRequest)
+		return
+	}
+
+	// The season is the game's own, whatever page the form was sent from
+	game, ok := h.authorizeGame(w, r, gameID)
+	if !ok {
+		return
+	}
+	seasonID := game.SeasonID
+
+	h.Logger.Printf("PARAM: Game ID = %d, Season ID = %d", gameID, seasonID)
+
+	redirectURL := h.path("/season/" + strconv.Itoa(seasonID))
+
+	err = h.Repo.CancelGame(gameID)
+	if err != nil {
+		h.Logger.Printf("ERROR: Cancelling game in database: %v", err)
+		status, message := gameErrorResponse(err, "Failed to cancel game")
+		if status == http.StatusNotFound || status == http.StatusInternalServerError {
+			http.Error(w, message, status)
+			return
+		}
+		h.Flash.Set(w, flash.LevelError, message)
+		h.Logger.Printf("REDIRECT: To %s", redirectURL)
+		http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: Game cancelled successfully")
+	h.Flash.Set(w, flash.LevelWarning, "Game cancelled")
+
+	// Redirect back to season page
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// RSVPHandler handles a player's answer to an upcoming game
+func (h *Handler) RSVPHandler(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Printf("ACTION: RSVPHandler - Processing RSVP")
+
+	if err := r.ParseForm(); err != nil {
+		h.Logger.Printf("ERROR: Parsing form data: %v", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	// Parse form values
+	gameID, err := strconv.Atoi(r.FormValue("game_id"))
+	if err != nil {
+		h.Logger.Printf("ERROR: Invalid game_id: %s", r.FormValue("game_id"))
+		http.Error(w, "Invalid game ID", http.StatusBadRequest)
+		return
+	}
+
+	// The season is the game's own, whatever page the form was sent from
+	game, ok := h.authorizeGame(w, r, gameID)
+	if !ok {
+		return
+	}
+	seasonID := game.SeasonID
+
+	// Members answer for themselves; only admins may answer for others
+	ownPlayerID, err := h.Repo.GetMemberPlayer(h.currentMember(r).ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		h.Logger.Printf("ERROR: Getting member's player failed: %v", err)
+		http.Error(w, "Failed to save answer", http.StatusInternalServerError)
+		return
+	}
+
+	playerID := ownPlayerID
+	if value := r.FormValue("player_id"); value != "" {
+		playerID, err = strconv.Atoi(value)
+		if err != nil {
+			h.Logger.Printf("ERROR: Invalid player_id: %s", value)
+			http.Error(w, "Invalid player ID", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if playerID == 0 || (playerID != ownPlayerID && !currentRole(r).CanManage()) {
+		h.Logger.Printf("AUTH: Role %q may not answer for player %d (own player %d)", currentRole(r), playerID, ownPlayerID)
+		h.forbidden(w, r, "You can only answer for yourself. Ask an admin to link your account to your player.")
+		return
+	}
+
+	answer := r.FormValue("status")
+	h.Logger.Printf("PARAM: Game ID = %d, Season ID = %d, Player ID = %d, Status = %s",
+		gameID, seasonID, playerID, answer)
+
+	redirectURL := h.path("/season/" + strconv.Itoa(seasonID))
+
+	err = h.Repo.SetRSVP(gameID, playerID, answer)
+	if err != nil {
+		h.Logger.Printf("ERROR: Saving RSVP in database: %v", err)
+		status, message := gameErrorResponse(err, "Failed to save answer")
+		if status == http.StatusNotFound || status == http.StatusInternalServerError {
+			http.Error(w, message, status)
+			return
+		}
+		h.Flash.Set(w, flash.LevelError, message)
+		h.Logger.Printf("REDIRECT: To %s", redirectURL)
+		http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+		return
+	}
+
+	h.Logger.Printf("SUCCESS: RSVP saved successfully")
+	h.Flash.Set(w, flash.LevelSuccess, "Answer saved")
+
+	// Redirect back to season page
+	h.Logger.Printf("REDIRECT: To %s", redirectURL)
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
@@ -0,0 +1,119 @@
+// Package ical writes calendars in the iCalendar format (RFC 5545), so that
+// games can be subscribed to from phone and desktop calendars.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// prodID identifies the application that created a calendar
+const prodID = "-//pokerhans//Poker Hans//EN"
+
+// maxLineOctets is the longest content line allowed before it must be folded
+const maxLineOctets = 75
+
+// Event statuses
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Event is an all-day event
+type Event struct {
+	// UID identifies the event across updates; it must never change
+	UID string
+
+	// Sequence is increased with every significant change, such as a new date
+	Sequence int
+
+	Date        time.Time
+	Summary     string
+	Description string
+	Status      string
+}
+
+// Calendar is a named list of events
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Encode writes the calendar to w. stamp is the time the calendar was
+// created, written as DTSTAMP of every event.
+func (c Calendar) Encode(w io.Writer, stamp time.Time) error {
+	bw := bufio.NewWriter(w)
+
+	write := func(name, value string) {
+		writeLine(bw, name+":"+value)
+	}
+
+	write("BEGIN", "VCALENDAR")
+	write("VERSION", "2.0")
+	write("PRODID", prodID)
+	write("CALSCALE", "GREGORIAN")
+	write("METHOD", "PUBLISH")
+	if c.Name != "" {
+		write("X-WR-CALNAME", Escape(c.Name))
+	}
+
+	for _, e := range c.Events {
+		write("BEGIN", "VEVENT")
+		write("UID", e.UID)
+		write("DTSTAMP", stamp.UTC().Format("20060102T150405Z"))
+		write("DTSTART;VALUE=DATE", e.Date.Format("20060102"))
+		write("DTEND;VALUE=DATE", e.Date.AddDate(0, 0, 1).Format("20060102"))
+		write("SEQUENCE", fmt.Sprint(e.Sequence))
+		write("SUMMARY", Escape(e.Summary))
+		if e.Description != "" {
+			write("DESCRIPTION", Escape(e.Description))
+		}
+		if e.Status != "" {
+			write("STATUS", e.Status)
+		}
+		write("TRANSP", "TRANSPARENT")
+		write("END", "VEVENT")
+	}
+
+	write("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// Escape escapes a text value: backslashes, semicolons and commas are
+// prefixed with a backslash and line breaks become \n
+func Escape(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// writeLine writes a content line ending in CRLF. Lines longer than 75
+// octets are folded onto continuation lines starting with a space, without
+// splitting a UTF-8 character.
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+
+		// The leading space of a continuation line counts towards its length
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
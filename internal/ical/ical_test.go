@@ -0,0 +1,92 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEscape(t *testing.T) {
+	tests := map[string]string{
+		"Poker at Anna's":      "Poker at Anna's",
+		"Winner: A, second: B": `Winner: A\, second: B`,
+		"a;b":                  `a\;b`,
+		`back\slash`:           `back\\slash`,
+		"line\nbreak":          `line\nbreak`,
+		"windows\r\nbreak":     `windows\nbreak`,
+	}
+	for input, expected := range tests {
+		if got := Escape(input); got != expected {
+			t.Errorf("Escape(%q): Expected %q, got %q", input, expected, got)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	cal := Calendar{
+		Name: "Spring 2025",
+		Events: []Event{{
+			UID:         "game-7@pokerhans",
+			Sequence:    2,
+			Date:        time.Date(2025, time.March, 7, 0, 0, 0, 0, time.UTC),
+			Summary:     "Poker at Anna's",
+			Description: "1st Max\n2nd Lisa",
+			Status:      StatusConfirmed,
+		}},
+	}
+
+	var buf bytes.Buffer
+	stamp := time.Date(2025, time.March, 1, 12, 30, 0, 0, time.UTC)
+	if err := cal.Encode(&buf, stamp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	for _, line := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Spring 2025\r\n",
+		"UID:game-7@pokerhans\r\n",
+		"DTSTAMP:20250301T123000Z\r\n",
+		"DTSTART;VALUE=DATE:20250307\r\n",
+		"DTEND;VALUE=DATE:20250308\r\n",
+		"SEQUENCE:2\r\n",
+		"SUMMARY:Poker at Anna's\r\n",
+		`DESCRIPTION:1st Max\n2nd Lisa` + "\r\n",
+		"STATUS:CONFIRMED\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, out)
+		}
+	}
+}
+
+func TestFolding(t *testing.T) {
+	var buf bytes.Buffer
+	cal := Calendar{Events: []Event{{
+		UID:     "game-1@pokerhans",
+		Summary: strings.Repeat("ü", 100),
+	}}}
+	if err := cal.Encode(&buf, time.Now()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var summary string
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("Expected lines of at most %d octets, got %d: %q", maxLineOctets, len(line), line)
+		}
+		if strings.HasPrefix(line, "SUMMARY:") {
+			summary = line
+		} else if summary != "" && strings.HasPrefix(line, " ") {
+			summary += line[1:]
+		} else if summary != "" {
+			break
+		}
+	}
+
+	if expected := "SUMMARY:" + strings.Repeat("ü", 100); summary != expected {
+		t.Errorf("Expected unfolded summary %q, got %q", expected, summary)
+	}
+}
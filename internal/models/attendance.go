@@ -0,0 +1,298 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Attendance statuses. Yes, no and maybe are answers given before a game;
+// attended is set for everyone who took part once the game is played.
+const (
+	AttendanceYes      = "yes"
+	AttendanceNo       = "no"
+	AttendanceMaybe    = "maybe"
+	AttendanceAttended = "attended"
+)
+
+// Attendance errors
+var (
+	ErrInvalidRSVP = errors.New("invalid RSVP answer")
+	ErrRSVPClosed  = errors.New("RSVPs are only possible for upcoming games")
+	ErrNotOnRoster = errors.New("player is not on the season's roster")
+)
+
+// Attendance is a player's answer to a game, or their presence at it
+type Attendance struct {
+	GameID     int       `json:"game_id"`
+	PlayerID   int       `json:"player_id"`
+	PlayerName string    `json:"player_name"`
+	Status     string    `json:"status"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// AttendanceCounts counts the attendance of a game by status
+type AttendanceCounts struct {
+	Yes      int `json:"yes"`
+	No       int `json:"no"`
+	Maybe    int `json:"maybe"`
+	Attended int `json:"attended"`
+}
+
+// SeasonAttendance counts the played games of a season a player attended
+type SeasonAttendance struct {
+	SeasonID   int    `json:"season_id"`
+	SeasonName string `json:"season_name"`
+	Attended   int    `json:"attended"`
+	Games      int    `json:"games"`
+}
+
+// RatePercent returns the share of the season's games attended as a percentage
+func (a SeasonAttendance) RatePercent() float64 {
+	if a.Games == 0 {
+		return 0
+	}
+	return float64(a.Attended) / float64(a.Games) * 100
+}
+
+// IsValidRSVP reports whether status is an answer a player can give before a game
+func IsValidRSVP(status string) bool {
+	return status == AttendanceYes || status == AttendanceNo || status == AttendanceMaybe
+}
+
+// AttendanceCounts returns the number of players per attendance status
+func (g Game) AttendanceCounts() AttendanceCounts {
+	var c AttendanceCounts
+	for _, a := range g.Attendance {
+		switch a.Status {
+		case AttendanceYes:
+			c.Yes++
+		case AttendanceNo:
+			c.No++
+		case AttendanceMaybe:
+			c.Maybe++
+		case AttendanceAttended:
+			c.Attended++
+		}
+	}
+	return c
+}
+
+// AttendeesWith returns the attendance entries of a game with the given status
+func (g Game) AttendeesWith(status string) []Attendance {
+	var attendees []Attendance
+	for _, a := range g.Attendance {
+		if a.Status == status {
+			attendees = append(attendees, a)
+		}
+	}
+	return attendees
+}
+
+// ParticipantPool splits players into those expected at a game and the rest.
+// The host and everyone who answered yes or maybe, or attended, are expected.
+// If nobody besides the host answered, all players are expected.
+func ParticipantPool(game Game, players []Player) (expected, others []Player) {
+	coming := map[int]bool{game.HostID: true}
+	for _, a := range game.Attendance {
+		if a.Status == AttendanceYes || a.Status == AttendanceMaybe || a.Status == AttendanceAttended {
+			coming[a.PlayerID] = true
+		}
+	}
+	if len(coming) == 1 {
+		return players, nil
+	}
+
+	for _, p := range players {
+		if coming[p.ID] {
+			expected = append(expected, p)
+		} else {
+			others = append(others, p)
+		}
+	}
+	return expected, others
+}
+
+// SetRSVP stores a player's answer to an upcoming game, replacing an earlier
+// one. Only players on the roster of the game's season can answer; others get
+// ErrNotOnRoster.
+func (r *Repository) SetRSVP(gameID, playerID int, status string) error {
+	if !IsValidRSVP(status) {
+		return ErrInvalidRSVP
+	}
+
+	if err := r.checkGameEditable(gameID); err != nil {
+		return err
+	}
+
+	var gameStatus string
+	if err := r.DB.QueryRow("SELECT status FROM games WHERE id = ?", gameID).Scan(&gameStatus); err != nil {
+		return err
+	}
+	if !(Game{Status: gameStatus}).IsUpcoming() {
+		return ErrRSVPClosed
+	}
+
+	if err := r.checkPlayersExist([]int{playerID}); err != nil {
+		return err
+	}
+
+	var onRoster bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM season_players sp
+			JOIN games g ON g.season_id = sp.season_id
+			WHERE g.id = ? AND sp.player_id = ?
+		)
+	`
+	if err := r.DB.QueryRow(query, gameID, playerID).Scan(&onRoster); err != nil {
+		return err
+	}
+	if !onRoster {
+		return ErrNotOnRoster
+	}
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query = `
+		INSERT INTO game_attendance (game_id, player_id, status)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE status = VALUES(status)
+	`
+	if _, err := tx.Exec(query, gameID, playerID, status); err != nil {
+		return err
+	}
+
+	// The answers are part of the game's calendar event
+	if _, err := tx.Exec("UPDATE games SET revision = revision + 1 WHERE id = ?", gameID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetPlayerAttendance returns how many played games a player attended in
+// each season they attended any, newest season first
+func (r *Repository) GetPlayerAttendance(playerID int) ([]SeasonAttendance, error) {
+	query := `
+		SELECT
+			s.id,
+			s.name,
+			COUNT(a.id) as attended,
+			COUNT(DISTINCT g.id) as games
+		FROM
+			seasons s
+		JOIN
+			games g ON g.season_id = s.id AND g.status = 'played'
+		LEFT JOIN
+			game_attendance a ON a.game_id = g.id AND a.player_id = ? AND a.status = 'attended'
+		WHERE
+			s.group_id = ?
+		GROUP BY
+			s.id, s.name
+		HAVING
+			attended > 0
+		ORDER BY
+			s.id DESC
+	`
+
+	rows, err := r.DB.Query(query, playerID, r.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seasons []SeasonAttendance
+	for rows.Next() {
+		var a SeasonAttendance
+		if err := rows.Scan(&a.SeasonID, &a.SeasonName, &a.Attended, &a.Games); err != nil {
+			return nil, err
+		}
+		seasons = append(seasons, a)
+	}
+
+	return seasons, nil
+}
+
+// recordAttendance marks the host and every player in the results or ledger
+// of a played game as attended. Earlier answers of the others are kept, so
+// it stays visible who said yes but did not come.
+func recordAttendance(tx *sql.Tx, gameID, hostID int, finishingOrder []int, ledger []LedgerEntry) error {
+	if _, err := tx.Exec("DELETE FROM game_attendance WHERE game_id = ? AND status = ?", gameID, AttendanceAttended); err != nil {
+		return err
+	}
+
+	playerIDs := append([]int{hostID}, finishingOrder...)
+	for _, e := range ledger {
+		playerIDs = append(playerIDs, e.PlayerID)
+	}
+
+	query := `
+		INSERT INTO game_attendance (game_id, player_id, status)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE status = VALUES(status)
+	`
+	seen := make(map[int]bool, len(playerIDs))
+	for _, id := range playerIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if _, err := tx.Exec(query, gameID, id, AttendanceAttended); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attachAttendance loads the attendance of all games matching the given
+// condition on the games table (aliased g) and attaches it to the games
+func (r *Repository) attachAttendance(games []Game, condition string, args ...interface{}) error {
+	if len(games) == 0 {
+		return nil
+	}
+
+	query := `
+		SELECT
+			a.game_id,
+			a.player_id,
+			p.name,
+			a.status,
+			a.updated_at
+		FROM
+			game_attendance a
+		JOIN
+			games g ON a.game_id = g.id
+		JOIN
+			players p ON a.player_id = p.id
+		WHERE
+			` + condition + `
+		ORDER BY
+			a.game_id, p.name
+	`
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	byGame := make(map[int][]Attendance)
+	for rows.Next() {
+		var a Attendance
+		if err := rows.Scan(&a.GameID, &a.PlayerID, &a.PlayerName, &a.Status, &a.UpdatedAt); err != nil {
+			return err
+		}
+		byGame[a.GameID] = append(byGame[a.GameID], a)
+	}
+
+	for i := range games {
+		games[i].Attendance = byGame[games[i].ID]
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestIsValidRSVP(t *testing.T) {
+	tests := map[string]bool{
+		AttendanceYes:      true,
+		AttendanceNo:       true,
+		AttendanceMaybe:    true,
+		AttendanceAttended: false,
+		"":                 false,
+		"YES":              false,
+	}
+	for status, expected := range tests {
+		if got := IsValidRSVP(status); got != expected {
+			t.Errorf("IsValidRSVP(%q): Expected %v, got %v", status, expected, got)
+		}
+	}
+}
+
+func TestAttendanceCounts(t *testing.T) {
+	game := Game{Attendance: []Attendance{
+		{PlayerID: 1, Status: AttendanceYes},
+		{PlayerID: 2, Status: AttendanceYes},
+		{PlayerID: 3, Status: AttendanceMaybe},
+		{PlayerID: 4, Status: AttendanceNo},
+		{PlayerID: 5, Status: AttendanceAttended},
+	}}
+
+	expected := AttendanceCounts{Yes: 2, No: 1, Maybe: 1, Attended: 1}
+	if got := game.AttendanceCounts(); got != expected {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+
+	if yes := game.AttendeesWith(AttendanceYes); len(yes) != 2 || yes[0].PlayerID != 1 || yes[1].PlayerID != 2 {
+		t.Errorf("Expected players 1 and 2 to have answered yes, got %v", yes)
+	}
+}
+
+func TestParticipantPool(t *testing.T) {
+	players := []Player{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}, {ID: 3, Name: "C"}, {ID: 4, Name: "D"}}
+
+	// Without answers, everyone is expected
+	expected, others := ParticipantPool(Game{HostID: 1}, players)
+	if len(expected) != 4 || len(others) != 0 {
+		t.Errorf("Expected all players without answers, got %v and %v", expected, others)
+	}
+
+	// Only the host and those who answered yes or maybe are expected
+	game := Game{HostID: 1, Attendance: []Attendance{
+		{PlayerID: 2, Status: AttendanceMaybe},
+		{PlayerID: 3, Status: AttendanceNo},
+	}}
+	expected, others = ParticipantPool(game, players)
+	if len(expected) != 2 || expected[0].ID != 1 || expected[1].ID != 2 {
+		t.Errorf("Expected players 1 and 2 to be expected, got %v", expected)
+	}
+	if len(others) != 2 || others[0].ID != 3 || others[1].ID != 4 {
+		t.Errorf("Expected players 3 and 4 as others, got %v", others)
+	}
+}
+
+func TestSeasonAttendanceRatePercent(t *testing.T) {
+	if got := (SeasonAttendance{Attended: 3, Games: 4}).RatePercent(); got != 75 {
+		t.Errorf("Expected 75, got %v", got)
+	}
+	if got := (SeasonAttendance{}).RatePercent(); got != 0 {
+		t.Errorf("Expected 0 without games, got %v", got)
+	}
+}
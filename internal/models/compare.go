@@ -0,0 +1,206 @@
+package models
+
+import (
+	"time"
+)
+
+// podiumSize is the number of places that count as a podium finish
+const podiumSize = 3
+
+// SharedGame is a played game two players both took part in, with the
+// finishing position of each (0 if they were not ranked)
+type SharedGame struct {
+	GameID     int       `json:"game_id"`
+	GameDate   time.Time `json:"game_date"`
+	SeasonID   int       `json:"season_id"`
+	SeasonName string    `json:"season_name"`
+	HostID     int       `json:"host_id"`
+	HostName   string    `json:"host_name"`
+	PositionA  int       `json:"position_a"`
+	PositionB  int       `json:"position_b"`
+}
+
+// AheadA reports whether the first player finished ahead of the second. A
+// ranked player is ahead of an unranked one.
+func (g SharedGame) AheadA() bool {
+	return g.PositionA > 0 && (g.PositionB == 0 || g.PositionA < g.PositionB)
+}
+
+// AheadB reports whether the second player finished ahead of the first
+func (g SharedGame) AheadB() bool {
+	return g.PositionB > 0 && (g.PositionA == 0 || g.PositionB < g.PositionA)
+}
+
+// PlacementA returns the first player's position as an ordinal, or "" if unranked
+func (g SharedGame) PlacementA() string {
+	if g.PositionA == 0 {
+		return ""
+	}
+	return ordinal(g.PositionA)
+}
+
+// PlacementB returns the second player's position as an ordinal, or "" if unranked
+func (g SharedGame) PlacementB() string {
+	if g.PositionB == 0 {
+		return ""
+	}
+	return ordinal(g.PositionB)
+}
+
+// HostRecord is how a player did in the games hosted by another
+type HostRecord struct {
+	Games   int `json:"games"`
+	Wins    int `json:"wins"`
+	Podiums int `json:"podiums"`
+	Ahead   int `json:"ahead"`
+}
+
+// HeadToHead compares two players over the games they both took part in
+type HeadToHead struct {
+	PlayerA Player       `json:"player_a"`
+	PlayerB Player       `json:"player_b"`
+	Games   []SharedGame `json:"games"`
+
+	// AheadA and AheadB count the games each finished ahead of the other
+	AheadA int `json:"ahead_a"`
+	AheadB int `json:"ahead_b"`
+
+	WinsA    int `json:"wins_a"`
+	WinsB    int `json:"wins_b"`
+	PodiumsA int `json:"podiums_a"`
+	PodiumsB int `json:"podiums_b"`
+
+	// SharedPodiums counts the games both finished in the top three
+	SharedPodiums int `json:"shared_podiums"`
+
+	// AAtB is the first player's record when the second hosts, BAtA the other way round
+	AAtB HostRecord `json:"a_at_b"`
+	BAtA HostRecord `json:"b_at_a"`
+}
+
+// CompareGames sums up the shared games of two players
+func CompareGames(a, b Player, games []SharedGame) HeadToHead {
+	h := HeadToHead{PlayerA: a, PlayerB: b, Games: games}
+
+	for _, g := range games {
+		podiumA := g.PositionA > 0 && g.PositionA <= podiumSize
+		podiumB := g.PositionB > 0 && g.PositionB <= podiumSize
+
+		if g.AheadA() {
+			h.AheadA++
+		}
+		if g.AheadB() {
+			h.AheadB++
+		}
+		if g.PositionA == 1 {
+			h.WinsA++
+		}
+		if g.PositionB == 1 {
+			h.WinsB++
+		}
+		if podiumA {
+			h.PodiumsA++
+		}
+		if podiumB {
+			h.PodiumsB++
+		}
+		if podiumA && podiumB {
+			h.SharedPodiums++
+		}
+
+		switch g.HostID {
+		case b.ID:
+			h.AAtB.add(g.PositionA, g.AheadA())
+		case a.ID:
+			h.BAtA.add(g.PositionB, g.AheadB())
+		}
+	}
+
+	return h
+}
+
+// add counts a game with the guest's position
+func (r *HostRecord) add(position int, ahead bool) {
+	r.Games++
+	if position == 1 {
+		r.Wins++
+	}
+	if position > 0 && position <= podiumSize {
+		r.Podiums++
+	}
+	if ahead {
+		r.Ahead++
+	}
+}
+
+// GetHeadToHead compares two players over the played games they both
+// hosted, attended or were ranked in, newest first. A seasonID of 0
+// compares across all seasons.
+func (r *Repository) GetHeadToHead(playerA, playerB, seasonID int) (HeadToHead, error) {
+	a, err := r.GetPlayer(playerA)
+	if err != nil {
+		return HeadToHead{}, err
+	}
+	b, err := r.GetPlayer(playerB)
+	if err != nil {
+		return HeadToHead{}, err
+	}
+
+	query := `
+		SELECT
+			g.id,
+			g.game_date,
+			s.id,
+			s.name,
+			g.host_id,
+			host.name,
+			COALESCE(ra.position, 0),
+			COALESCE(rb.position, 0)
+		FROM
+			games g
+		JOIN
+			seasons s ON g.season_id = s.id
+		JOIN
+			players host ON g.host_id = host.id
+		LEFT JOIN
+			game_results ra ON ra.game_id = g.id AND ra.player_id = ?
+		LEFT JOIN
+			game_results rb ON rb.game_id = g.id AND rb.player_id = ?
+		WHERE
+			g.status = 'played'
+			AND s.group_id = ?
+			AND (? = 0 OR g.season_id = ?)
+			AND (g.host_id = ? OR ra.id IS NOT NULL OR EXISTS (
+				SELECT 1 FROM game_attendance a WHERE a.game_id = g.id AND a.player_id = ? AND a.status = 'attended'))
+			AND (g.host_id = ? OR rb.id IS NOT NULL OR EXISTS (
+				SELECT 1 FROM game_attendance a WHERE a.game_id = g.id AND a.player_id = ? AND a.status = 'attended'))
+		ORDER BY
+			g.game_date DESC, g.id DESC
+	`
+
+	rows, err := r.DB.Query(query, playerA, playerB, r.GroupID, seasonID, seasonID, playerA, playerA, playerB, playerB)
+	if err != nil {
+		return HeadToHead{}, err
+	}
+	defer rows.Close()
+
+	var games []SharedGame
+	for rows.Next() {
+		var g SharedGame
+		if err := rows.Scan(
+			&g.GameID,
+			&g.GameDate,
+			&g.SeasonID,
+			&g.SeasonName,
+			&g.HostID,
+			&g.HostName,
+			&g.PositionA,
+			&g.PositionB,
+		); err != nil {
+			return HeadToHead{}, err
+		}
+		games = append(games, g)
+	}
+
+	return CompareGames(a, b, games), nil
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestSharedGameAhead(t *testing.T) {
+	tests := []struct {
+		a, b           int
+		aheadA, aheadB bool
+	}{
+		{1, 2, true, false},
+		{4, 2, false, true},
+		{3, 0, true, false},
+		{0, 5, false, true},
+		{0, 0, false, false},
+	}
+	for _, tt := range tests {
+		g := SharedGame{PositionA: tt.a, PositionB: tt.b}
+		if g.AheadA() != tt.aheadA || g.AheadB() != tt.aheadB {
+			t.Errorf("Positions %d and %d: Expected ahead %v/%v, got %v/%v",
+				tt.a, tt.b, tt.aheadA, tt.aheadB, g.AheadA(), g.AheadB())
+		}
+	}
+}
+
+func TestCompareGames(t *testing.T) {
+	a := Player{ID: 1, Name: "Anna"}
+	b := Player{ID: 2, Name: "Max"}
+	games := []SharedGame{
+		{GameID: 1, HostID: 2, PositionA: 1, PositionB: 3},
+		{GameID: 2, HostID: 2, PositionA: 0, PositionB: 2},
+		{GameID: 3, HostID: 1, PositionA: 2, PositionB: 1},
+		{GameID: 4, HostID: 9, PositionA: 5, PositionB: 4},
+	}
+	h := CompareGames(a, b, games)
+
+	if h.AheadA != 1 || h.AheadB != 3 {
+		t.Errorf("Expected Anna ahead once and Max three times, got %d and %d", h.AheadA, h.AheadB)
+	}
+	if h.WinsA != 1 || h.WinsB != 1 {
+		t.Errorf("Expected one win each, got %d and %d", h.WinsA, h.WinsB)
+	}
+	if h.PodiumsA != 2 || h.PodiumsB != 3 || h.SharedPodiums != 2 {
+		t.Errorf("Expected podiums 2, 3 and 2 shared, got %d, %d and %d", h.PodiumsA, h.PodiumsB, h.SharedPodiums)
+	}
+
+	expectedAAtB := HostRecord{Games: 2, Wins: 1, Podiums: 1, Ahead: 1}
+	if h.AAtB != expectedAAtB {
+		t.Errorf("Expected Anna at Max's %+v, got %+v", expectedAAtB, h.AAtB)
+	}
+	expectedBAtA := HostRecord{Games: 1, Wins: 1, Podiums: 1, Ahead: 1}
+	if h.BAtA != expectedBAtA {
+		t.Errorf("Expected Max at Anna's %+v, got %+v", expectedBAtA, h.BAtA)
+	}
+}
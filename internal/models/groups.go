@@ -0,0 +1,120 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Group errors
+var (
+	ErrInvalidSlug   = errors.New("slug must be 2 to 64 lowercase letters, digits or dashes")
+	ErrDuplicateSlug = errors.New("slug is already taken")
+)
+
+// DefaultGroupSlug is the slug of the group that all data from before groups
+// existed was moved into
+const DefaultGroupSlug = "hans"
+
+// slugPattern matches a valid group slug: lowercase letters, digits and
+// single dashes, starting and ending with a letter or digit
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Group is a poker circle. It owns its seasons and players.
+type Group struct {
+	ID        int       `json:"id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsValidSlug reports whether s can be used as the slug of a group in URLs
+func IsValidSlug(s string) bool {
+	return len(s) >= 2 && len(s) <= 64 && slugPattern.MatchString(s)
+}
+
+// ForGroup returns a repository scoped to the given group
+func (r *Repository) ForGroup(groupID int) *Repository {
+	return &Repository{DB: r.DB, GroupID: groupID}
+}
+
+// GetGroups returns all groups by name
+func (r *Repository) GetGroups() ([]Group, error) {
+	rows, err := r.DB.Query("SELECT id, slug, name, created_at FROM poker_groups ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []Group
+	for rows.Next() {
+		var g Group
+		if err := rows.Scan(&g.ID, &g.Slug, &g.Name, &g.CreatedAt); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+
+	return groups, nil
+}
+
+// GetGroupBySlug returns the group with the given slug
+func (r *Repository) GetGroupBySlug(slug string) (Group, error) {
+	var g Group
+	query := "SELECT id, slug, name, created_at FROM poker_groups WHERE slug = ?"
+	err := r.DB.QueryRow(query, slug).Scan(&g.ID, &g.Slug, &g.Name, &g.CreatedAt)
+	return g, err
+}
+
+// CreateGroup adds a new group and returns its ID. It returns ErrInvalidSlug
+// or ErrDuplicateSlug if the slug cannot be used.
+func (r *Repository) CreateGroup(slug, name string) (int, error) {
+	if !IsValidSlug(slug) {
+		return 0, ErrInvalidSlug
+	}
+
+	result, err := r.DB.Exec("INSERT INTO poker_groups (slug, name) VALUES (?, ?)", slug, name)
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return 0, ErrDuplicateSlug
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// GetSeasonGroup returns the group a season belongs to, so that links from
+// before groups existed can be redirected
+func (r *Repository) GetSeasonGroup(seasonID int) (Group, error) {
+	var g Group
+	query := `
+		SELECT pg.id, pg.slug, pg.name, pg.created_at
+		FROM poker_groups pg
+		JOIN seasons s ON s.group_id = pg.id
+		WHERE s.id = ?
+	`
+	err := r.DB.QueryRow(query, seasonID).Scan(&g.ID, &g.Slug, &g.Name, &g.CreatedAt)
+	return g, err
+}
+
+// GetPlayerGroup returns the group a player belongs to, so that links from
+// before groups existed can be redirected
+func (r *Repository) GetPlayerGroup(playerID int) (Group, error) {
+	var g Group
+	query := `
+		SELECT pg.id, pg.slug, pg.name, pg.created_at
+		FROM poker_groups pg
+		JOIN players p ON p.group_id = pg.id
+		WHERE p.id = ?
+	`
+	err := r.DB.QueryRow(query, playerID).Scan(&g.ID, &g.Slug, &g.Name, &g.CreatedAt)
+	return g, err
+}
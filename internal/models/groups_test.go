@@ -0,0 +1,25 @@
+package models
+
+import "testing"
+
+func TestIsValidSlug(t *testing.T) {
+	tests := map[string]bool{
+		"hans":          true,
+		"friday-poker":  true,
+		"club42":        true,
+		"a":             false,
+		"":              false,
+		"Hans":          false,
+		"friday--poker": false,
+		"-hans":         false,
+		"hans-":         false,
+		"hans poker":    false,
+		"hans/poker":    false,
+	}
+
+	for slug, expected := range tests {
+		if got := IsValidSlug(slug); got != expected {
+			t.Errorf("Expected IsValidSlug(%q) to be %v, got %v", slug, expected, got)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Cents is an amount of money in euro cents
+type Cents int64
+
+// String formats the amount in euros, e.g. "€12.50" or "-€3.00"
+func (c Cents) String() string {
+	sign := ""
+	if c < 0 {
+		sign = "-"
+		c = -c
+	}
+	return fmt.Sprintf("%s€%d.%02d", sign, c/100, c%100)
+}
+
+// ParseCents parses a euro amount such as "12", "12.5" or "12,50" into cents
+func ParseCents(s string) (Cents, error) {
+	s = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "€"))
+	if s == "" {
+		return 0, nil
+	}
+
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	euros, fraction, _ := strings.Cut(strings.Replace(s, ",", ".", 1), ".")
+	if euros == "" {
+		euros = "0"
+	}
+	if len(fraction) > 2 {
+		return 0, fmt.Errorf("invalid amount %q: more than two decimals", s)
+	}
+	fraction += strings.Repeat("0", 2-len(fraction))
+
+	e, err := strconv.ParseInt(euros, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	f, err := strconv.ParseInt(fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+
+	c := Cents(e*100 + f)
+	if negative {
+		c = -c
+	}
+	return c, nil
+}
+
+// LedgerEntry holds the money a player put into and took out of a game
+type LedgerEntry struct {
+	GameID     int    `json:"game_id"`
+	PlayerID   int    `json:"player_id"`
+	PlayerName string `json:"player_name"`
+	BuyIn      Cents  `json:"buy_in_cents"`
+	Rebuys     Cents  `json:"rebuy_cents"`
+	Payout     Cents  `json:"payout_cents"`
+}
+
+// Net returns the player's profit (or loss, if negative) in the game
+func (e LedgerEntry) Net() Cents {
+	return e.Payout - e.BuyIn - e.Rebuys
+}
+
+// PlayerBalance is a player's net profit over a number of games
+type PlayerBalance struct {
+	PlayerID int    `json:"player_id"`
+	Name     string `json:"name"`
+	Net      Cents  `json:"net_cents"`
+}
+
+// Pot returns the sum of all buy-ins and rebuys of a game
+func (g Game) Pot() Cents {
+	var pot Cents
+	for _, e := range g.Ledger {
+		pot += e.BuyIn + e.Rebuys
+	}
+	return pot
+}
+
+// Payouts returns the sum of all payouts of a game
+func (g Game) Payouts() Cents {
+	var payouts Cents
+	for _, e := range g.Ledger {
+		payouts += e.Payout
+	}
+	return payouts
+}
+
+// IsBalanced reports whether the payouts of a game equal its pot
+func (g Game) IsBalanced() bool {
+	return g.Pot() == g.Payouts()
+}
+
+// Balances returns the net result of every player in the game's ledger
+func (g Game) Balances() []PlayerBalance {
+	balances := make([]PlayerBalance, len(g.Ledger))
+	for i, e := range g.Ledger {
+		balances[i] = PlayerBalance{PlayerID: e.PlayerID, Name: e.PlayerName, Net: e.Net()}
+	}
+	return balances
+}
+
+// LedgerIsBalanced reports whether the payouts of the given entries equal
+// their buy-ins and rebuys
+func LedgerIsBalanced(entries []LedgerEntry) bool {
+	return Game{Ledger: entries}.IsBalanced()
+}
+
+// GetSeasonBalances returns the net profit of every player with ledger
+// entries in the given season, biggest winner first
+func (r *Repository) GetSeasonBalances(seasonID int) ([]PlayerBalance, error) {
+	query := `
+		SELECT
+			p.id,
+			p.name,
+			SUM(l.payout_cents - l.buy_in_cents - l.rebuy_cents) as net_cents
+		FROM
+			game_ledger l
+		JOIN
+			games g ON l.game_id = g.id
+		JOIN
+			players p ON l.player_id = p.id
+		WHERE
+			g.season_id = ? AND g.status = 'played' AND ` + gameInGroup + `
+		GROUP BY
+			p.id, p.name
+		ORDER BY
+			net_cents DESC, p.name
+	`
+
+	rows, err := r.DB.Query(query, seasonID, r.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balances []PlayerBalance
+	for rows.Next() {
+		var b PlayerBalance
+		if err := rows.Scan(&b.PlayerID, &b.Name, &b.Net); err != nil {
+			return nil, err
+		}
+		balances = append(balances, b)
+	}
+
+	return balances, nil
+}
+
+// GetPlayerNet returns a player's all-time net profit
+func (r *Repository) GetPlayerNet(playerID int) (Cents, error) {
+	var net Cents
+	query := `
+		SELECT COALESCE(SUM(l.payout_cents - l.buy_in_cents - l.rebuy_cents), 0)
+		FROM game_ledger l
+		JOIN games g ON l.game_id = g.id
+		WHERE l.player_id = ? AND ` + gameInGroup + `
+	`
+	err := r.DB.QueryRow(query, playerID, r.GroupID).Scan(&net)
+	return net, err
+}
+
+// insertLedger stores the ledger entries of a game
+func insertLedger(tx *sql.Tx, gameID int, entries []LedgerEntry) error {
+	query := `
+		INSERT INTO game_ledger (game_id, player_id, buy_in_cents, rebuy_cents, payout_cents)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	for _, e := range entries {
+		if _, err := tx.Exec(query, gameID, e.PlayerID, e.BuyIn, e.Rebuys, e.Payout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attachLedger loads the ledger entries of all games matching the given
+// condition on the games table (aliased g) and attaches them to the games
+func (r *Repository) attachLedger(games []Game, condition string, args ...interface{}) error {
+	if len(games) == 0 {
+		return nil
+	}
+
+	query := `
+		SELECT
+			l.game_id,
+			l.player_id,
+			p.name,
+			l.buy_in_cents,
+			l.rebuy_cents,
+			l.payout_cents
+		FROM
+			game_ledger l
+		JOIN
+			games g ON l.game_id = g.id
+		JOIN
+			players p ON l.player_id = p.id
+		WHERE
+			` + condition + `
+		ORDER BY
+			l.game_id, p.name
+	`
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	byGame := make(map[int][]LedgerEntry)
+	for rows.Next() {
+		var e LedgerEntry
+		if err := rows.Scan(&e.GameID, &e.PlayerID, &e.PlayerName, &e.BuyIn, &e.Rebuys, &e.Payout); err != nil {
+			return err
+		}
+		byGame[e.GameID] = append(byGame[e.GameID], e)
+	}
+
+	for i := range games {
+		games[i].Ledger = byGame[games[i].ID]
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestParseCents(t *testing.T) {
+	cases := map[string]Cents{
+		"":       0,
+		"12":     1200,
+		"12.5":   1250,
+		"12,50":  1250,
+		"€7.05":  705,
+		".5":     50,
+		"-3":     -300,
+		" 0.99 ": 99,
+	}
+
+	for input, want := range cases {
+		got, err := ParseCents(input)
+		if err != nil {
+			t.Errorf("ParseCents(%q): unexpected error: %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseCents(%q): expected %d, got %d", input, want, got)
+		}
+	}
+
+	for _, input := range []string{"abc", "1.234", "1.2.3", "ten"} {
+		if _, err := ParseCents(input); err == nil {
+			t.Errorf("ParseCents(%q): expected an error", input)
+		}
+	}
+}
+
+func TestCentsString(t *testing.T) {
+	cases := map[Cents]string{
+		0:     "€0.00",
+		1250:  "€12.50",
+		5:     "€0.05",
+		-1999: "-€19.99",
+	}
+
+	for c, want := range cases {
+		if got := c.String(); got != want {
+			t.Errorf("Cents(%d).String(): expected %s, got %s", int64(c), want, got)
+		}
+	}
+}
+
+func TestGameBalance(t *testing.T) {
+	g := Game{Ledger: []LedgerEntry{
+		{PlayerID: 1, BuyIn: 1000, Rebuys: 500, Payout: 2000},
+		{PlayerID: 2, BuyIn: 1000, Payout: 500},
+	}}
+
+	if g.Pot() != 2500 {
+		t.Errorf("Expected pot 2500, got %d", g.Pot())
+	}
+	if !g.IsBalanced() {
+		t.Errorf("Expected game to be balanced")
+	}
+	if g.Ledger[0].Net() != 500 || g.Ledger[1].Net() != -500 {
+		t.Errorf("Expected nets 500 and -500, got %d and %d", g.Ledger[0].Net(), g.Ledger[1].Net())
+	}
+
+	g.Ledger[1].Payout = 0
+	if g.IsBalanced() {
+		t.Errorf("Expected game to be unbalanced")
+	}
+}
@@ -0,0 +1,234 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Game statuses
+const (
+	GameStatusPlanned   = "planned"
+	GameStatusPlayed    = "played"
+	GameStatusPostponed = "postponed"
+	GameStatusCancelled = "cancelled"
+)
+
+// ErrInvalidTransition is returned when a game cannot move to the requested status
+var ErrInvalidTransition = errors.New("game cannot change to this status")
+
+// gameTransitions lists the statuses a game may move to from each status.
+// Played games are final; a cancelled game can be planned again.
+var gameTransitions = map[string][]string{
+	GameStatusPlanned:   {GameStatusPostponed, GameStatusCancelled, GameStatusPlayed},
+	GameStatusPostponed: {GameStatusPostponed, GameStatusCancelled, GameStatusPlayed},
+	GameStatusCancelled: {GameStatusPlanned, GameStatusPlayed},
+}
+
+// CanTransition reports whether a game may move from one status to another
+func CanTransition(from, to string) bool {
+	for _, s := range gameTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// GameDateChange is a recorded change of the date of a game
+type GameDateChange struct {
+	ID        int       `json:"id"`
+	GameID    int       `json:"game_id"`
+	OldDate   time.Time `json:"old_date"`
+	NewDate   time.Time `json:"new_date"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsPlayed reports whether the game has been played
+func (g Game) IsPlayed() bool {
+	return g.Status == GameStatusPlayed
+}
+
+// IsUpcoming reports whether the game is planned or postponed
+func (g Game) IsUpcoming() bool {
+	return g.Status == GameStatusPlanned || g.Status == GameStatusPostponed
+}
+
+// PlayedGames returns the games that have been played
+func PlayedGames(games []Game) []Game {
+	var played []Game
+	for _, g := range games {
+		if g.IsPlayed() {
+			played = append(played, g)
+		}
+	}
+	return played
+}
+
+// UpcomingGames returns the planned and postponed games
+func UpcomingGames(games []Game) []Game {
+	var upcoming []Game
+	for _, g := range games {
+		if g.IsUpcoming() {
+			upcoming = append(upcoming, g)
+		}
+	}
+	return upcoming
+}
+
+// PlanGame puts a game that has not been played yet into a season and returns
+// its ID. A cancelled game of the same host is planned again instead of
+// adding a new one.
+func (r *Repository) PlanGame(seasonID, hostID int, gameDate time.Time) (int, error) {
+	existing, err := r.hostGame(seasonID, hostID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	gameID := 0
+	if err == nil && existing.Status == GameStatusCancelled {
+		gameID = existing.ID
+	}
+
+	if err := r.ValidateGame(gameID, seasonID, hostID, gameDate, nil, nil); err != nil {
+		return 0, err
+	}
+
+	if gameID != 0 {
+		return gameID, r.transitionGame(gameID, GameStatusPlanned, &gameDate)
+	}
+
+	query := "INSERT INTO games (season_id, host_id, game_date, status) VALUES (?, ?, ?, ?)"
+	result, err := r.DB.Exec(query, seasonID, hostID, gameDate, GameStatusPlanned)
+	if err != nil {
+		return 0, translateGameError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// PostponeGame moves a game that has not been played to a new date within
+// its season
+func (r *Repository) PostponeGame(gameID int, newDate time.Time) error {
+	if err := r.checkGameEditable(gameID); err != nil {
+		return err
+	}
+	if err := r.checkGameDate(gameID, newDate); err != nil {
+		return err
+	}
+	return r.transitionGame(gameID, GameStatusPostponed, &newDate)
+}
+
+// CancelGame cancels a game that has not been played. The host counts as not
+// visited again.
+func (r *Repository) CancelGame(gameID int) error {
+	if err := r.checkGameEditable(gameID); err != nil {
+		return err
+	}
+	return r.transitionGame(gameID, GameStatusCancelled, nil)
+}
+
+// GetGameDateChanges returns the date history of a game, oldest first
+func (r *Repository) GetGameDateChanges(gameID int) ([]GameDateChange, error) {
+	query := `
+		SELECT c.id, c.game_id, c.old_date, c.new_date, c.created_at
+		FROM game_date_changes c
+		JOIN games g ON c.game_id = g.id
+		WHERE c.game_id = ? AND ` + gameInGroup + `
+		ORDER BY c.created_at, c.id
+	`
+	rows, err := r.DB.Query(query, gameID, r.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []GameDateChange
+	for rows.Next() {
+		var c GameDateChange
+		if err := rows.Scan(&c.ID, &c.GameID, &c.OldDate, &c.NewDate, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+
+	return changes, nil
+}
+
+// transitionGame moves a game to a new status and, if given, a new date
+func (r *Repository) transitionGame(gameID int, status string, newDate *time.Time) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := transitionGameTx(tx, gameID, status, newDate); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// transitionGameTx is transitionGame within an existing transaction
+func transitionGameTx(tx *sql.Tx, gameID int, status string, newDate *time.Time) error {
+	var current string
+	err := tx.QueryRow("SELECT status FROM games WHERE id = ? FOR UPDATE", gameID).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUnknownGame
+	}
+	if err != nil {
+		return err
+	}
+
+	if !CanTransition(current, status) {
+		return ErrInvalidTransition
+	}
+
+	if newDate != nil {
+		if err := changeGameDate(tx, gameID, *newDate); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE games SET status = ?, revision = revision + 1 WHERE id = ?", status, gameID)
+	return err
+}
+
+// changeGameDate sets the date of a game and records the change, if the
+// date differs from the current one
+func changeGameDate(tx *sql.Tx, gameID int, newDate time.Time) error {
+	var oldDate time.Time
+	err := tx.QueryRow("SELECT game_date FROM games WHERE id = ? FOR UPDATE", gameID).Scan(&oldDate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUnknownGame
+	}
+	if err != nil {
+		return err
+	}
+
+	if oldDate.Format("2006-01-02") == newDate.Format("2006-01-02") {
+		return nil
+	}
+
+	if _, err := tx.Exec("UPDATE games SET game_date = ?, revision = revision + 1 WHERE id = ?", newDate, gameID); err != nil {
+		return err
+	}
+
+	query := "INSERT INTO game_date_changes (game_id, old_date, new_date) VALUES (?, ?, ?)"
+	_, err = tx.Exec(query, gameID, oldDate, newDate)
+	return err
+}
+
+// hostGame returns the game of a host in a season of the group, whatever its
+// status. It returns sql.ErrNoRows if the host has no game in the season.
+func (r *Repository) hostGame(seasonID, hostID int) (Game, error) {
+	var g Game
+	query := "SELECT g.id, g.season_id, g.host_id, g.game_date, g.status FROM games g WHERE g.season_id = ? AND g.host_id = ? AND " + gameInGroup
+	err := r.DB.QueryRow(query, seasonID, hostID, r.GroupID).Scan(&g.ID, &g.SeasonID, &g.HostID, &g.GameDate, &g.Status)
+	return g, err
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	allowed := [][2]string{
+		{GameStatusPlanned, GameStatusPostponed},
+		{GameStatusPlanned, GameStatusCancelled},
+		{GameStatusPlanned, GameStatusPlayed},
+		{GameStatusPostponed, GameStatusPostponed},
+		{GameStatusPostponed, GameStatusPlayed},
+		{GameStatusCancelled, GameStatusPlanned},
+	}
+	for _, tr := range allowed {
+		if !CanTransition(tr[0], tr[1]) {
+			t.Errorf("Expected %s -> %s to be allowed", tr[0], tr[1])
+		}
+	}
+
+	forbidden := [][2]string{
+		{GameStatusPlayed, GameStatusPlanned},
+		{GameStatusPlayed, GameStatusPostponed},
+		{GameStatusPlayed, GameStatusCancelled},
+		{GameStatusCancelled, GameStatusPostponed},
+		{GameStatusPlanned, GameStatusPlanned},
+		{"", GameStatusPlanned},
+	}
+	for _, tr := range forbidden {
+		if CanTransition(tr[0], tr[1]) {
+			t.Errorf("Expected %s -> %s to be forbidden", tr[0], tr[1])
+		}
+	}
+}
+
+func TestPlayedAndUpcomingGames(t *testing.T) {
+	games := []Game{
+		{ID: 1, Status: GameStatusPlayed},
+		{ID: 2, Status: GameStatusPlanned},
+		{ID: 3, Status: GameStatusPostponed},
+		{ID: 4, Status: GameStatusCancelled},
+	}
+
+	played := PlayedGames(games)
+	if len(played) != 1 || played[0].ID != 1 {
+		t.Errorf("Expected only game 1 to be played, got %v", played)
+	}
+
+	upcoming := UpcomingGames(games)
+	if len(upcoming) != 2 || upcoming[0].ID != 2 || upcoming[1].ID != 3 {
+		t.Errorf("Expected games 2 and 3 to be upcoming, got %v", upcoming)
+	}
+}
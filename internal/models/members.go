@@ -0,0 +1,211 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// minPasswordLength is the shortest password a member can have
+const minPasswordLength = 8
+
+// Role is what a member may do in a group
+type Role string
+
+// Member roles. Admins manage seasons and players and may change any game,
+// members record games of the current season and viewers can only read.
+const (
+	RoleAdmin  Role = "admin"
+	RoleMember Role = "member"
+	RoleViewer Role = "viewer"
+)
+
+// Member errors
+var (
+	ErrInvalidRole    = errors.New("role must be admin, member or viewer")
+	ErrInvalidLogin   = errors.New("wrong email or password")
+	ErrShortPassword  = errors.New("password must be at least 8 characters")
+	ErrDuplicateEmail = errors.New("a member with this email already exists")
+	ErrPlayerTaken    = errors.New("another member already is this player")
+)
+
+// dummyHash is compared against when no member has the given email, so that
+// a failed login takes as long whether or not the email is known
+var dummyHash = []byte("$2a$10$PhHxUG200gdpfwn9Qo5qe.3fbfNTkxKuFepiYvhjoC.BpDHTrB1dy")
+
+// Member is a person who can log in to make changes. Members are shared by
+// all groups and can belong to several of them.
+type Member struct {
+	ID           int       `json:"id"`
+	Email        string    `json:"email"`
+	Name         string    `json:"name"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// IsValid reports whether r is one of the member roles
+func (r Role) IsValid() bool {
+	return r == RoleAdmin || r == RoleMember || r == RoleViewer
+}
+
+// CanManage reports whether the role may manage seasons, players, rosters
+// and schedules
+func (r Role) CanManage() bool {
+	return r == RoleAdmin
+}
+
+// CanChangeSeason reports whether the role may record and change games of
+// a season. Admins may change every season, members only the current one.
+func (r Role) CanChangeSeason(seasonID, currentSeasonID int) bool {
+	if r == RoleAdmin {
+		return true
+	}
+	return r == RoleMember && seasonID != 0 && seasonID == currentSeasonID
+}
+
+// NormalizeEmail returns the form an email is stored and looked up in
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// HashPassword returns the bcrypt hash of a password. It returns
+// ErrShortPassword for passwords that are too short.
+func HashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", ErrShortPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches the member's password hash
+func (m Member) CheckPassword(password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(m.PasswordHash), []byte(password)) == nil
+}
+
+// CreateMember adds a new member and returns their ID
+func (r *Repository) CreateMember(email, name, password string) (int, error) {
+	hash, err := HashPassword(password)
+	if err != nil {
+		return 0, err
+	}
+
+	query := "INSERT INTO members (email, name, password_hash) VALUES (?, ?, ?)"
+	result, err := r.DB.Exec(query, NormalizeEmail(email), name, hash)
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return 0, ErrDuplicateEmail
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// GetMemberByEmail returns the member with the given email
+func (r *Repository) GetMemberByEmail(email string) (Member, error) {
+	var m Member
+	query := "SELECT id, email, name, password_hash, created_at FROM members WHERE email = ?"
+	err := r.DB.QueryRow(query, NormalizeEmail(email)).Scan(&m.ID, &m.Email, &m.Name, &m.PasswordHash, &m.CreatedAt)
+	return m, err
+}
+
+// SetMemberPassword replaces the password of a member
+func (r *Repository) SetMemberPassword(memberID int, password string) error {
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	return r.execAffectingOne("UPDATE members SET password_hash = ? WHERE id = ?", hash, memberID)
+}
+
+// Authenticate returns the member with the given email and password. It
+// returns ErrInvalidLogin if the email is unknown or the password is wrong.
+func (r *Repository) Authenticate(email, password string) (Member, error) {
+	m, err := r.GetMemberByEmail(email)
+	if errors.Is(err, sql.ErrNoRows) {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return Member{}, ErrInvalidLogin
+	}
+	if err != nil {
+		return Member{}, err
+	}
+
+	if !m.CheckPassword(password) {
+		return Member{}, ErrInvalidLogin
+	}
+	return m, nil
+}
+
+// AddGroupMember makes a member part of the group with the given role. If
+// they already are, their role is changed.
+func (r *Repository) AddGroupMember(memberID int, role Role) error {
+	if !role.IsValid() {
+		return ErrInvalidRole
+	}
+
+	query := `
+		INSERT INTO group_members (group_id, member_id, role)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE role = VALUES(role)
+	`
+	_, err := r.DB.Exec(query, r.GroupID, memberID, role)
+	return err
+}
+
+// GetGroupRole returns the role of a member in the group. It returns
+// sql.ErrNoRows if they are not a member of the group.
+func (r *Repository) GetGroupRole(memberID int) (Role, error) {
+	var role Role
+	query := "SELECT role FROM group_members WHERE group_id = ? AND member_id = ?"
+	err := r.DB.QueryRow(query, r.GroupID, memberID).Scan(&role)
+	return role, err
+}
+
+// SetMemberPlayer makes a member of the group one of its players, so they
+// can answer for themselves. It returns ErrUnknownPlayer if the player is
+// not the group's, ErrPlayerTaken if another member already is the player
+// and sql.ErrNoRows if the member does not belong to the group.
+func (r *Repository) SetMemberPlayer(memberID, playerID int) error {
+	if err := r.checkPlayersExist([]int{playerID}); err != nil {
+		return err
+	}
+
+	if _, err := r.GetGroupRole(memberID); err != nil {
+		return err
+	}
+
+	query := "UPDATE group_members SET player_id = ? WHERE group_id = ? AND member_id = ?"
+	_, err := r.DB.Exec(query, playerID, r.GroupID, memberID)
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return ErrPlayerTaken
+	}
+	return err
+}
+
+// GetMemberPlayer returns the ID of the player a member is in the group. It
+// returns sql.ErrNoRows if they are not one of its players.
+func (r *Repository) GetMemberPlayer(memberID int) (int, error) {
+	var playerID sql.NullInt64
+	query := "SELECT player_id FROM group_members WHERE group_id = ? AND member_id = ?"
+	if err := r.DB.QueryRow(query, r.GroupID, memberID).Scan(&playerID); err != nil {
+		return 0, err
+	}
+	if !playerID.Valid {
+		return 0, sql.ErrNoRows
+	}
+	return int(playerID.Int64), nil
+}
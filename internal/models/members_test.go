@@ -0,0 +1,124 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	if _, err := HashPassword("short"); !errors.Is(err, ErrShortPassword) {
+		t.Errorf("Expected ErrShortPassword, got %v", err)
+	}
+
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if hash == "correct horse" {
+		t.Errorf("Expected the password to be hashed")
+	}
+
+	m := Member{PasswordHash: hash}
+	if !m.CheckPassword("correct horse") {
+		t.Errorf("Expected the password to match its hash")
+	}
+	if m.CheckPassword("correct horse ") {
+		t.Errorf("Expected another password not to match")
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := map[string]string{
+		"anna@example.com":    "anna@example.com",
+		" Anna@Example.COM\n": "anna@example.com",
+		"":                    "",
+	}
+
+	for email, expected := range tests {
+		if got := NormalizeEmail(email); got != expected {
+			t.Errorf("Expected NormalizeEmail(%q) to be %q, got %q", email, expected, got)
+		}
+	}
+}
+
+func TestSessionToken(t *testing.T) {
+	a, err := NewSessionToken()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	b, err := NewSessionToken()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if a == b {
+		t.Errorf("Expected two tokens to differ")
+	}
+
+	if HashSessionToken(a) != HashSessionToken(a) {
+		t.Errorf("Expected the hash of a token to be stable")
+	}
+	if got := len(HashSessionToken(a)); got != 64 {
+		t.Errorf("Expected a hash of 64 characters, got %d", got)
+	}
+	if HashSessionToken(a) == a {
+		t.Errorf("Expected the token not to be stored as is")
+	}
+}
+
+func TestRoleCanChangeSeason(t *testing.T) {
+	tests := []struct {
+		role     Role
+		season   int
+		current  int
+		expected bool
+	}{
+		{RoleAdmin, 3, 3, true},
+		{RoleAdmin, 1, 3, true},
+		{RoleAdmin, 1, 0, true},
+		{RoleMember, 3, 3, true},
+		{RoleMember, 1, 3, false},
+		{RoleMember, 0, 0, false},
+		{RoleViewer, 3, 3, false},
+		{"", 3, 3, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.role.CanChangeSeason(tt.season, tt.current); got != tt.expected {
+			t.Errorf("Expected %q changing season %d with current season %d to be %v, got %v",
+				tt.role, tt.season, tt.current, tt.expected, got)
+		}
+	}
+}
+
+func TestRoleCanManage(t *testing.T) {
+	tests := map[Role]bool{
+		RoleAdmin:  true,
+		RoleMember: false,
+		RoleViewer: false,
+		"":         false,
+		"owner":    false,
+	}
+
+	for role, expected := range tests {
+		if got := role.CanManage(); got != expected {
+			t.Errorf("Expected %q CanManage to be %v, got %v", role, expected, got)
+		}
+	}
+}
+
+func TestRoleIsValid(t *testing.T) {
+	tests := map[Role]bool{
+		RoleAdmin:  true,
+		RoleMember: true,
+		RoleViewer: true,
+		"":         false,
+		"Admin":    false,
+		"owner":    false,
+	}
+
+	for role, expected := range tests {
+		if got := role.IsValid(); got != expected {
+			t.Errorf("Expected %q IsValid to be %v, got %v", role, expected, got)
+		}
+	}
+}
@@ -2,20 +2,63 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"time"
 )
 
+// Season statuses
+const (
+	SeasonStatusActive   = "active"
+	SeasonStatusArchived = "archived"
+)
+
 // Season represents a poker season
 type Season struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+	Status    string     `json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// IsArchived reports whether the season is archived and therefore read-only
+func (s Season) IsArchived() bool {
+	return s.Status == SeasonStatusArchived
+}
+
+// Contains reports whether a date falls within the season's date range. A
+// missing start or end date leaves that side of the range open.
+func (s Season) Contains(date time.Time) bool {
+	day := date.Format("2006-01-02")
+	if s.StartDate != nil && day < s.StartDate.Format("2006-01-02") {
+		return false
+	}
+	if s.EndDate != nil && day > s.EndDate.Format("2006-01-02") {
+		return false
+	}
+	return true
+}
+
+// DateRange describes the season's date range for display, or returns ""
+// if it has none
+func (s Season) DateRange() string {
+	switch {
+	case s.StartDate != nil && s.EndDate != nil:
+		return s.StartDate.Format("Jan 02, 2006") + " – " + s.EndDate.Format("Jan 02, 2006")
+	case s.StartDate != nil:
+		return "since " + s.StartDate.Format("Jan 02, 2006")
+	case s.EndDate != nil:
+		return "until " + s.EndDate.Format("Jan 02, 2006")
+	}
+	return ""
 }
 
 // Player represents a poker player
 type Player struct {
 	ID        int       `json:"id"`
 	Name      string    `json:"name"`
+	Active    bool      `json:"active"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -27,12 +70,26 @@ type Game struct {
 	WinnerID      *int      `json:"winner_id"`
 	SecondPlaceID *int      `json:"second_place_id"`
 	GameDate      time.Time `json:"game_date"`
+	Status        string    `json:"status"`
 	CreatedAt     time.Time `json:"created_at"`
 
 	// Additional fields for display
 	HostName        string `json:"host_name"`
 	WinnerName      string `json:"winner_name"`
 	SecondPlaceName string `json:"second_place_name"`
+
+	// Results holds the full finishing order, winner first
+	Results []GameResult `json:"results"`
+
+	// Ledger holds the buy-ins, rebuys and payouts of the participants
+	Ledger []LedgerEntry `json:"ledger"`
+
+	// Attendance holds the answers of the players and, once played, who came
+	Attendance []Attendance `json:"attendance"`
+
+	// Revision counts the changes to the game's date, status, host, results
+	// and answers, so that newer versions of it can be told apart
+	Revision int `json:"revision"`
 }
 
 // PlayerStatus includes information about whether a player has hosted a game
@@ -40,11 +97,23 @@ type PlayerStatus struct {
 	Player
 	HasHosted bool      `json:"has_hosted"`
 	GameDate  time.Time `json:"game_date,omitempty"`
+
+	// GameStatus is the status of the player's game this season, if any
+	GameStatus string `json:"game_status,omitempty"`
+}
+
+// HasUpcomingGame reports whether the player's game is planned but not played yet
+func (p PlayerStatus) HasUpcomingGame() bool {
+	return p.GameStatus == GameStatusPlanned || p.GameStatus == GameStatusPostponed
 }
 
-// Repository provides methods to interact with the database
+// Repository provides methods to interact with the database. Apart from the
+// group methods, every method only sees and changes the data of the group
+// the repository is scoped to with ForGroup. An unscoped repository sees no
+// seasons, players or games.
 type Repository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	GroupID int
 }
 
 // NewRepository creates a new Repository with the given database connection
@@ -52,10 +121,14 @@ func NewRepository(db *sql.DB) *Repository {
 	return &Repository{DB: db}
 }
 
-// GetSeasons returns all seasons
+// gameInGroup is a condition on the games table (aliased g) that matches the
+// games of a group. It takes the group ID as argument.
+const gameInGroup = "g.season_id IN (SELECT id FROM seasons WHERE group_id = ?)"
+
+// GetSeasons returns all seasons of the group
 func (r *Repository) GetSeasons() ([]Season, error) {
-	query := "SELECT id, name, created_at FROM seasons ORDER BY id DESC"
-	rows, err := r.DB.Query(query)
+	query := "SELECT id, name, start_date, end_date, status, created_at FROM seasons WHERE group_id = ? ORDER BY id DESC"
+	rows, err := r.DB.Query(query, r.GroupID)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +137,7 @@ func (r *Repository) GetSeasons() ([]Season, error) {
 	var seasons []Season
 	for rows.Next() {
 		var s Season
-		if err := rows.Scan(&s.ID, &s.Name, &s.CreatedAt); err != nil {
+		if err := rows.Scan(&s.ID, &s.Name, &s.StartDate, &s.EndDate, &s.Status, &s.CreatedAt); err != nil {
 			return nil, err
 		}
 		seasons = append(seasons, s)
@@ -73,25 +146,34 @@ func (r *Repository) GetSeasons() ([]Season, error) {
 	return seasons, nil
 }
 
-// GetSeasonPlayers returns all players for a given season with their hosting status
+// GetSeasonPlayers returns the players of a given season with their hosting status.
+// A player belongs to the season if they are on its roster and active, or if
+// they host a game in the season. Only played games count as hosted; cancelled
+// games are ignored.
 func (r *Repository) GetSeasonPlayers(seasonID int) ([]PlayerStatus, error) {
 	query := `
 		SELECT 
 			p.id, 
 			p.name, 
+			p.active,
 			p.created_at,
-			g.id IS NOT NULL as has_hosted,
-			IF(g.id IS NULL, '0001-01-01', DATE_FORMAT(g.game_date, '%Y-%m-%d')) as game_date
+			COALESCE(g.status = 'played', FALSE) as has_hosted,
+			IF(g.id IS NULL, '0001-01-01', DATE_FORMAT(g.game_date, '%Y-%m-%d')) as game_date,
+			COALESCE(g.status, '') as game_status
 		FROM 
 			players p
+		LEFT JOIN
+			season_players sp ON sp.player_id = p.id AND sp.season_id = ?
 		LEFT JOIN (
-			SELECT * FROM games WHERE season_id = ?
+			SELECT * FROM games WHERE season_id = ? AND status <> 'cancelled'
 		) g ON p.id = g.host_id
+		WHERE
+			p.group_id = ? AND (g.id IS NOT NULL OR (sp.id IS NOT NULL AND p.active))
 		ORDER BY 
-			CASE WHEN g.id IS NULL THEN 0 ELSE 1 END, p.name
+			CASE WHEN g.status = 'played' THEN 1 ELSE 0 END, p.name
 	`
 
-	rows, err := r.DB.Query(query, seasonID)
+	rows, err := r.DB.Query(query, seasonID, seasonID, r.GroupID)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +183,7 @@ func (r *Repository) GetSeasonPlayers(seasonID int) ([]PlayerStatus, error) {
 	for rows.Next() {
 		var p PlayerStatus
 		var gameDateStr string
-		if err := rows.Scan(&p.ID, &p.Name, &p.CreatedAt, &p.HasHosted, &gameDateStr); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.Active, &p.CreatedAt, &p.HasHosted, &gameDateStr, &p.GameStatus); err != nil {
 			return nil, err
 		}
 
@@ -116,18 +198,101 @@ func (r *Repository) GetSeasonPlayers(seasonID int) ([]PlayerStatus, error) {
 	return players, nil
 }
 
-// AddGame adds a new game to the database
-func (r *Repository) AddGame(seasonID, hostID int, winnerID, secondPlaceID *int, gameDate time.Time) error {
+// AddGame adds a new game with its finishing order and ledger to the database.
+// finishingOrder lists the player IDs from winner to first player out; the
+// first two also fill the legacy winner and second place columns.
+// The game is checked with ValidateGame first. If the host already has a
+// game this season that has not been played, that game is recorded as played
+// instead of adding a new one.
+func (r *Repository) AddGame(seasonID, hostID int, gameDate time.Time, finishingOrder []int, ledger []LedgerEntry) error {
+	existing, err := r.hostGame(seasonID, hostID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	gameID := 0
+	if err == nil && existing.Status != GameStatusPlayed {
+		gameID = existing.ID
+	}
+
+	if err := r.ValidateGame(gameID, seasonID, hostID, gameDate, finishingOrder, ledger); err != nil {
+		return err
+	}
+
+	if gameID != 0 {
+		return translateGameError(r.replaceGame(gameID, hostID, gameDate, finishingOrder, ledger))
+	}
+	return translateGameError(r.insertGame(seasonID, hostID, gameDate, finishingOrder, ledger))
+}
+
+// insertGame stores a new game with its finishing order and ledger
+func (r *Repository) insertGame(seasonID, hostID int, gameDate time.Time, finishingOrder []int, ledger []LedgerEntry) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	winnerID, secondPlaceID := podium(finishingOrder)
+
 	query := `
-		INSERT INTO games (season_id, host_id, winner_id, second_place_id, game_date) 
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO games (season_id, host_id, winner_id, second_place_id, game_date, status) 
+		VALUES (?, ?, ?, ?, ?, ?)
 	`
-	_, err := r.DB.Exec(query, seasonID, hostID, winnerID, secondPlaceID, gameDate)
-	return err
+	result, err := tx.Exec(query, seasonID, hostID, winnerID, secondPlaceID, gameDate, GameStatusPlayed)
+	if err != nil {
+		return err
+	}
+
+	gameID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	if err := insertGameResults(tx, int(gameID), finishingOrder); err != nil {
+		return err
+	}
+
+	if err := insertLedger(tx, int(gameID), ledger); err != nil {
+		return err
+	}
+
+	if err := recordAttendance(tx, int(gameID), hostID, finishingOrder, ledger); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // GetGames returns all games for a given season
 func (r *Repository) GetGames(seasonID int) ([]Game, error) {
+	return r.queryGames("g.season_id = ?", seasonID)
+}
+
+// GetPlayedGames returns the played games of all seasons of the group
+func (r *Repository) GetPlayedGames() ([]Game, error) {
+	return r.queryGames("g.status = ?", GameStatusPlayed)
+}
+
+// GetGame returns a single game with its results and ledger
+func (r *Repository) GetGame(gameID int) (Game, error) {
+	games, err := r.queryGames("g.id = ?", gameID)
+	if err != nil {
+		return Game{}, err
+	}
+	if len(games) == 0 {
+		return Game{}, sql.ErrNoRows
+	}
+	return games[0], nil
+}
+
+// queryGames returns the games of the group matching the given condition on
+// the games table (aliased g), newest first, with their results and ledger
+// attached
+func (r *Repository) queryGames(condition string, args ...interface{}) ([]Game, error) {
+	condition = gameInGroup + " AND " + condition
+	args = append([]interface{}{r.GroupID}, args...)
+
 	query := `
 		SELECT 
 			g.id, 
@@ -136,10 +301,12 @@ func (r *Repository) GetGames(seasonID int) ([]Game, error) {
 			g.winner_id, 
 			g.second_place_id, 
 			g.game_date, 
+			g.status,
 			g.created_at,
 			host.name as host_name,
 			COALESCE(winner.name, '') as winner_name,
-			COALESCE(second.name, '') as second_place_name
+			COALESCE(second.name, '') as second_place_name,
+			g.revision
 		FROM 
 			games g
 		JOIN 
@@ -149,12 +316,12 @@ func (r *Repository) GetGames(seasonID int) ([]Game, error) {
 		LEFT JOIN 
 			players second ON g.second_place_id = second.id
 		WHERE 
-			g.season_id = ?
+			` + condition + `
 		ORDER BY 
 			g.game_date DESC
 	`
 
-	rows, err := r.DB.Query(query, seasonID)
+	rows, err := r.DB.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -170,23 +337,48 @@ func (r *Repository) GetGames(seasonID int) ([]Game, error) {
 			&g.WinnerID,
 			&g.SecondPlaceID,
 			&g.GameDate,
+			&g.Status,
 			&g.CreatedAt,
 			&g.HostName,
 			&g.WinnerName,
 			&g.SecondPlaceName,
+			&g.Revision,
 		); err != nil {
 			return nil, err
 		}
 		games = append(games, g)
 	}
 
+	if err := r.attachGameResults(games, condition, args...); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachLedger(games, condition, args...); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachAttendance(games, condition, args...); err != nil {
+		return nil, err
+	}
+
 	return games, nil
 }
 
-// GetAllPlayers returns all players in the system
+// GetAllPlayers returns all players of the group, including inactive ones
 func (r *Repository) GetAllPlayers() ([]Player, error) {
-	query := "SELECT id, name, created_at FROM players ORDER BY name"
-	rows, err := r.DB.Query(query)
+	query := "SELECT id, name, active, created_at FROM players WHERE group_id = ? ORDER BY name"
+	return r.queryPlayers(query, r.GroupID)
+}
+
+// GetActivePlayers returns all players that are still part of the group
+func (r *Repository) GetActivePlayers() ([]Player, error) {
+	query := "SELECT id, name, active, created_at FROM players WHERE group_id = ? AND active ORDER BY name"
+	return r.queryPlayers(query, r.GroupID)
+}
+
+// queryPlayers runs a query selecting id, name, active and created_at of players
+func (r *Repository) queryPlayers(query string, args ...interface{}) ([]Player, error) {
+	rows, err := r.DB.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -195,7 +387,7 @@ func (r *Repository) GetAllPlayers() ([]Player, error) {
 	var players []Player
 	for rows.Next() {
 		var p Player
-		if err := rows.Scan(&p.ID, &p.Name, &p.CreatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.Active, &p.CreatedAt); err != nil {
 			return nil, err
 		}
 		players = append(players, p)
@@ -204,9 +396,106 @@ func (r *Repository) GetAllPlayers() ([]Player, error) {
 	return players, nil
 }
 
-// UpdateGameDate updates the date of a specific game
+// UpdateGameDate updates the date of a specific game and records the change.
+// It returns ErrUnknownGame or ErrSeasonArchived if the game cannot be changed
+// and ErrDateOutsideSeason if the date does not fall within its season.
 func (r *Repository) UpdateGameDate(gameID int, newDate time.Time) error {
-	query := "UPDATE games SET game_date = ? WHERE id = ?"
-	_, err := r.DB.Exec(query, newDate, gameID)
+	if err := r.checkGameEditable(gameID); err != nil {
+		return err
+	}
+	if err := r.checkGameDate(gameID, newDate); err != nil {
+		return err
+	}
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := changeGameDate(tx, gameID, newDate); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateGame replaces the host, date, finishing order and ledger of a game.
+// It returns ErrUnknownGame if the game does not exist; otherwise the game is
+// checked with ValidateGame first.
+func (r *Repository) UpdateGame(gameID, hostID int, gameDate time.Time, finishingOrder []int, ledger []LedgerEntry) error {
+	var seasonID int
+	query := "SELECT g.season_id FROM games g WHERE g.id = ? AND " + gameInGroup
+	err := r.DB.QueryRow(query, gameID, r.GroupID).Scan(&seasonID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUnknownGame
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := r.ValidateGame(gameID, seasonID, hostID, gameDate, finishingOrder, ledger); err != nil {
+		return err
+	}
+	return translateGameError(r.replaceGame(gameID, hostID, gameDate, finishingOrder, ledger))
+}
+
+// replaceGame overwrites a game with its finishing order and ledger and
+// marks it as played
+func (r *Repository) replaceGame(gameID, hostID int, gameDate time.Time, finishingOrder []int, ledger []LedgerEntry) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	winnerID, secondPlaceID := podium(finishingOrder)
+
+	if err := changeGameDate(tx, gameID, gameDate); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE games
+		SET host_id = ?, winner_id = ?, second_place_id = ?, status = ?, revision = revision + 1
+		WHERE id = ?
+	`
+	if _, err := tx.Exec(query, hostID, winnerID, secondPlaceID, GameStatusPlayed, gameID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM game_results WHERE game_id = ?", gameID); err != nil {
+		return err
+	}
+	if err := insertGameResults(tx, gameID, finishingOrder); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM game_ledger WHERE game_id = ?", gameID); err != nil {
+		return err
+	}
+	if err := insertLedger(tx, gameID, ledger); err != nil {
+		return err
+	}
+
+	if err := recordAttendance(tx, gameID, hostID, finishingOrder, ledger); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteGame removes a game together with its results, ledger and settled
+// payments. It returns ErrUnknownGame or ErrSeasonArchived if the game
+// cannot be deleted.
+func (r *Repository) DeleteGame(gameID int) error {
+	if err := r.checkGameEditable(gameID); err != nil {
+		return err
+	}
+
+	err := r.execAffectingOne("DELETE FROM games WHERE id = ?", gameID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUnknownGame
+	}
 	return err
 }
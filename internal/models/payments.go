@@ -0,0 +1,144 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrNotInSettlement is returned for a settled transfer between players who
+// did not take part in the season or game it settles
+var ErrNotInSettlement = errors.New("player did not take part in the season or game")
+
+// SettlementPayment is a transfer between two players that has been marked
+// as settled. GameID is set when the transfer settled a single game.
+type SettlementPayment struct {
+	ID        int       `json:"id"`
+	SeasonID  int       `json:"season_id"`
+	GameID    *int      `json:"game_id"`
+	FromID    int       `json:"from_id"`
+	FromName  string    `json:"from_name"`
+	ToID      int       `json:"to_id"`
+	ToName    string    `json:"to_name"`
+	Amount    Cents     `json:"amount_cents"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GetSettlementPayments returns all settled transfers of a season, oldest first
+func (r *Repository) GetSettlementPayments(seasonID int) ([]SettlementPayment, error) {
+	query := `
+		SELECT
+			sp.id,
+			sp.season_id,
+			sp.game_id,
+			sp.from_player_id,
+			payer.name,
+			sp.to_player_id,
+			receiver.name,
+			sp.amount_cents,
+			sp.created_at
+		FROM
+			settlement_payments sp
+		JOIN
+			players payer ON sp.from_player_id = payer.id
+		JOIN
+			players receiver ON sp.to_player_id = receiver.id
+		JOIN
+			seasons s ON sp.season_id = s.id
+		WHERE
+			sp.season_id = ? AND s.group_id = ?
+		ORDER BY
+			sp.created_at, sp.id
+	`
+
+	rows, err := r.DB.Query(query, seasonID, r.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []SettlementPayment
+	for rows.Next() {
+		var p SettlementPayment
+		if err := rows.Scan(
+			&p.ID,
+			&p.SeasonID,
+			&p.GameID,
+			&p.FromID,
+			&p.FromName,
+			&p.ToID,
+			&p.ToName,
+			&p.Amount,
+			&p.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+
+	return payments, nil
+}
+
+// AddSettlementPayment marks a transfer as settled. The season, the players
+// and the game, if given, must belong to the group; otherwise it returns
+// ErrUnknownSeason, ErrUnknownPlayer or ErrUnknownGame. Both players must be
+// in the ledger of the game, or without a game on the season's roster or in
+// the ledger of one of its games; otherwise it returns ErrNotInSettlement.
+func (r *Repository) AddSettlementPayment(seasonID int, gameID *int, fromID, toID int, amount Cents) error {
+	if _, err := r.GetSeason(seasonID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUnknownSeason
+		}
+		return err
+	}
+	if err := r.checkPlayersExist([]int{fromID, toID}); err != nil {
+		return err
+	}
+	if gameID != nil {
+		var found int
+		query := "SELECT COUNT(*) FROM games WHERE id = ? AND season_id = ?"
+		if err := r.DB.QueryRow(query, *gameID, seasonID).Scan(&found); err != nil {
+			return err
+		}
+		if found == 0 {
+			return ErrUnknownGame
+		}
+	}
+	if err := r.checkSettlementPlayers(seasonID, gameID, fromID, toID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO settlement_payments (season_id, game_id, from_player_id, to_player_id, amount_cents)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	_, err := r.DB.Exec(query, seasonID, gameID, fromID, toID, amount)
+	return err
+}
+
+// checkSettlementPlayers returns ErrNotInSettlement unless both players took
+// part in what a transfer settles, see AddSettlementPayment
+func (r *Repository) checkSettlementPlayers(seasonID int, gameID *int, fromID, toID int) error {
+	query := `
+		SELECT COUNT(DISTINCT player_id) FROM (
+			SELECT sp.player_id FROM season_players sp WHERE sp.season_id = ?
+			UNION ALL
+			SELECT l.player_id FROM game_ledger l JOIN games g ON l.game_id = g.id WHERE g.season_id = ?
+		) season_part
+		WHERE player_id IN (?, ?)
+	`
+	args := []interface{}{seasonID, seasonID, fromID, toID}
+	if gameID != nil {
+		query = "SELECT COUNT(DISTINCT player_id) FROM game_ledger WHERE game_id = ? AND player_id IN (?, ?)"
+		args = []interface{}{*gameID, fromID, toID}
+	}
+
+	var found int
+	if err := r.DB.QueryRow(query, args...).Scan(&found); err != nil {
+		return err
+	}
+	if found != 2 {
+		return ErrNotInSettlement
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package models
+
+// PlayerStats summarises a player's record across all seasons
+type PlayerStats struct {
+	Player
+	GamesPlayed   int     `json:"games_played"`
+	GamesHosted   int     `json:"games_hosted"`
+	Wins          int     `json:"wins"`
+	SecondPlaces  int     `json:"second_places"`
+	SeasonsPlayed int     `json:"seasons_played"`
+	WinRate       float64 `json:"win_rate"`
+	Net           Cents   `json:"net_cents"`
+
+	// Attendance counts the games attended per season, newest season first
+	Attendance []SeasonAttendance `json:"attendance"`
+}
+
+// WinRatePercent returns the win rate as a percentage
+func (s PlayerStats) WinRatePercent() float64 {
+	return s.WinRate * 100
+}
+
+// PlayerGame is a game seen from the perspective of a single player
+type PlayerGame struct {
+	Game
+	SeasonName string `json:"season_name"`
+	Hosted     bool   `json:"hosted"`
+
+	// Position is the player's finishing position, or 0 if they were not ranked
+	Position int `json:"position"`
+}
+
+// Placement returns the player's finishing position as an ordinal such as
+// "3rd", or an empty string if they were not ranked
+func (pg PlayerGame) Placement() string {
+	if pg.Position == 0 {
+		return ""
+	}
+	return ordinal(pg.Position)
+}
+
+// GetPlayerStats returns the all-time statistics of a player. A player counts
+// as having played a game when they hosted it or are part of its results.
+func (r *Repository) GetPlayerStats(playerID int) (PlayerStats, error) {
+	player, err := r.GetPlayer(playerID)
+	if err != nil {
+		return PlayerStats{}, err
+	}
+
+	stats := PlayerStats{Player: player}
+
+	query := `
+		SELECT
+			COUNT(*) as games_played,
+			COALESCE(SUM(g.host_id = ?), 0) as games_hosted,
+			COALESCE(SUM(gr.position = 1), 0) as wins,
+			COALESCE(SUM(gr.position = 2), 0) as second_places,
+			COUNT(DISTINCT g.season_id) as seasons_played
+		FROM
+			games g
+		LEFT JOIN
+			game_results gr ON gr.game_id = g.id AND gr.player_id = ?
+		WHERE
+			g.status = 'played' AND (g.host_id = ? OR gr.id IS NOT NULL) AND ` + gameInGroup + `
+	`
+	err = r.DB.QueryRow(query, playerID, playerID, playerID, r.GroupID).Scan(
+		&stats.GamesPlayed,
+		&stats.GamesHosted,
+		&stats.Wins,
+		&stats.SecondPlaces,
+		&stats.SeasonsPlayed,
+	)
+	if err != nil {
+		return PlayerStats{}, err
+	}
+
+	if stats.GamesPlayed > 0 {
+		stats.WinRate = float64(stats.Wins) / float64(stats.GamesPlayed)
+	}
+
+	stats.Net, err = r.GetPlayerNet(playerID)
+	if err != nil {
+		return PlayerStats{}, err
+	}
+
+	stats.Attendance, err = r.GetPlayerAttendance(playerID)
+	if err != nil {
+		return PlayerStats{}, err
+	}
+
+	return stats, nil
+}
+
+// GetPlayerRecentGames returns the most recent games a player took part in,
+// newest first
+func (r *Repository) GetPlayerRecentGames(playerID, limit int) ([]PlayerGame, error) {
+	query := `
+		SELECT
+			g.id,
+			g.season_id,
+			g.host_id,
+			g.winner_id,
+			g.second_place_id,
+			g.game_date,
+			g.created_at,
+			host.name as host_name,
+			COALESCE(winner.name, '') as winner_name,
+			COALESCE(second.name, '') as second_place_name,
+			s.name as season_name,
+			COALESCE(gr.position, 0) as position
+		FROM
+			games g
+		JOIN
+			seasons s ON g.season_id = s.id
+		JOIN
+			players host ON g.host_id = host.id
+		LEFT JOIN
+			players winner ON g.winner_id = winner.id
+		LEFT JOIN
+			players second ON g.second_place_id = second.id
+		LEFT JOIN
+			game_results gr ON gr.game_id = g.id AND gr.player_id = ?
+		WHERE
+			g.status = 'played' AND (g.host_id = ? OR gr.id IS NOT NULL) AND s.group_id = ?
+		ORDER BY
+			g.game_date DESC, g.id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.DB.Query(query, playerID, playerID, r.GroupID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []PlayerGame
+	for rows.Next() {
+		var pg PlayerGame
+		if err := rows.Scan(
+			&pg.ID,
+			&pg.SeasonID,
+			&pg.HostID,
+			&pg.WinnerID,
+			&pg.SecondPlaceID,
+			&pg.GameDate,
+			&pg.CreatedAt,
+			&pg.HostName,
+			&pg.WinnerName,
+			&pg.SecondPlaceName,
+			&pg.SeasonName,
+			&pg.Position,
+		); err != nil {
+			return nil, err
+		}
+
+		pg.Hosted = pg.HostID == playerID
+		games = append(games, pg)
+	}
+
+	return games, nil
+}
+
+// GetPlayerGames returns every game a player is part of, newest first: the
+// games they host, are ranked in or answered yes or maybe to, and the
+// upcoming and cancelled games of seasons they are on the roster of
+func (r *Repository) GetPlayerGames(playerID int) ([]Game, error) {
+	condition := `(
+		g.host_id = ?
+		OR g.id IN (SELECT game_id FROM game_results WHERE player_id = ?)
+		OR g.id IN (SELECT game_id FROM game_attendance WHERE player_id = ? AND status <> 'no')
+		OR (g.status <> 'played' AND g.season_id IN (SELECT season_id FROM season_players WHERE player_id = ?))
+	)`
+	return r.queryGames(condition, playerID, playerID, playerID, playerID)
+}
@@ -0,0 +1,37 @@
+package models
+
+// GetPlayer returns a single player of the group by ID
+func (r *Repository) GetPlayer(playerID int) (Player, error) {
+	var p Player
+	query := "SELECT id, name, active, created_at FROM players WHERE id = ? AND group_id = ?"
+	err := r.DB.QueryRow(query, playerID, r.GroupID).Scan(&p.ID, &p.Name, &p.Active, &p.CreatedAt)
+	return p, err
+}
+
+// CreatePlayer adds a new active player to the group and returns their ID
+func (r *Repository) CreatePlayer(name string) (int, error) {
+	query := "INSERT INTO players (group_id, name, active) VALUES (?, ?, TRUE)"
+	result, err := r.DB.Exec(query, r.GroupID, name)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// RenamePlayer changes the name of a player
+func (r *Repository) RenamePlayer(playerID int, name string) error {
+	query := "UPDATE players SET name = ? WHERE id = ? AND group_id = ?"
+	return r.execAffectingOne(query, name, playerID, r.GroupID)
+}
+
+// SetPlayerActive activates or deactivates a player. Inactive players keep
+// their historical games but are no longer offered for new ones.
+func (r *Repository) SetPlayerActive(playerID int, active bool) error {
+	query := "UPDATE players SET active = ? WHERE id = ? AND group_id = ?"
+	return r.execAffectingOne(query, active, playerID, r.GroupID)
+}
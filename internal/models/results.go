@@ -0,0 +1,106 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// GameResult is a player's finishing position in a game
+type GameResult struct {
+	GameID     int    `json:"game_id"`
+	PlayerID   int    `json:"player_id"`
+	PlayerName string `json:"player_name"`
+	Position   int    `json:"position"`
+}
+
+// Placement returns the finishing position as an ordinal such as "1st"
+func (gr GameResult) Placement() string {
+	return ordinal(gr.Position)
+}
+
+// podium returns the winner and second place of a finishing order, or nil
+// where the order is too short
+func podium(finishingOrder []int) (winnerID, secondPlaceID *int) {
+	if len(finishingOrder) > 0 {
+		winnerID = &finishingOrder[0]
+	}
+	if len(finishingOrder) > 1 {
+		secondPlaceID = &finishingOrder[1]
+	}
+	return winnerID, secondPlaceID
+}
+
+// insertGameResults stores a finishing order, starting at position 1
+func insertGameResults(tx *sql.Tx, gameID int, finishingOrder []int) error {
+	query := "INSERT INTO game_results (game_id, player_id, position) VALUES (?, ?, ?)"
+	for i, playerID := range finishingOrder {
+		if _, err := tx.Exec(query, gameID, playerID, i+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attachGameResults loads the results of all games matching the given
+// condition on the games table (aliased g) and attaches them to the games
+func (r *Repository) attachGameResults(games []Game, condition string, args ...interface{}) error {
+	if len(games) == 0 {
+		return nil
+	}
+
+	query := `
+		SELECT
+			gr.game_id,
+			gr.player_id,
+			p.name,
+			gr.position
+		FROM
+			game_results gr
+		JOIN
+			games g ON gr.game_id = g.id
+		JOIN
+			players p ON gr.player_id = p.id
+		WHERE
+			` + condition + `
+		ORDER BY
+			gr.game_id, gr.position
+	`
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	byGame := make(map[int][]GameResult)
+	for rows.Next() {
+		var gr GameResult
+		if err := rows.Scan(&gr.GameID, &gr.PlayerID, &gr.PlayerName, &gr.Position); err != nil {
+			return err
+		}
+		byGame[gr.GameID] = append(byGame[gr.GameID], gr)
+	}
+
+	for i := range games {
+		games[i].Results = byGame[games[i].ID]
+	}
+
+	return nil
+}
+
+// ordinal formats a position as "1st", "2nd", "3rd", "4th" and so on
+func ordinal(n int) string {
+	suffix := "th"
+	switch n % 10 {
+	case 1:
+		suffix = "st"
+	case 2:
+		suffix = "nd"
+	case 3:
+		suffix = "rd"
+	}
+	if n%100 >= 11 && n%100 <= 13 {
+		suffix = "th"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestOrdinal(t *testing.T) {
+	cases := map[int]string{
+		1:   "1st",
+		2:   "2nd",
+		3:   "3rd",
+		4:   "4th",
+		11:  "11th",
+		12:  "12th",
+		13:  "13th",
+		21:  "21st",
+		102: "102nd",
+	}
+
+	for n, want := range cases {
+		if got := ordinal(n); got != want {
+			t.Errorf("ordinal(%d): expected %s, got %s", n, want, got)
+		}
+	}
+}
+
+func TestPodium(t *testing.T) {
+	winnerID, secondPlaceID := podium([]int{7, 3, 5})
+	if winnerID == nil || *winnerID != 7 {
+		t.Errorf("Expected winner 7, got %v", winnerID)
+	}
+	if secondPlaceID == nil || *secondPlaceID != 3 {
+		t.Errorf("Expected second place 3, got %v", secondPlaceID)
+	}
+
+	winnerID, secondPlaceID = podium([]int{7})
+	if winnerID == nil || *winnerID != 7 || secondPlaceID != nil {
+		t.Errorf("Expected only a winner for a single result")
+	}
+
+	winnerID, secondPlaceID = podium(nil)
+	if winnerID != nil || secondPlaceID != nil {
+		t.Errorf("Expected no podium without results")
+	}
+}
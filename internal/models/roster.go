@@ -0,0 +1,117 @@
+package models
+
+import (
+	"database/sql"
+)
+
+// GetSeasonRoster returns the players on the roster of a season
+func (r *Repository) GetSeasonRoster(seasonID int) ([]Player, error) {
+	query := `
+		SELECT
+			p.id,
+			p.name,
+			p.active,
+			p.created_at
+		FROM
+			players p
+		JOIN
+			season_players sp ON sp.player_id = p.id
+		WHERE
+			sp.season_id = ? AND p.group_id = ?
+		ORDER BY
+			p.name
+	`
+	return r.queryPlayers(query, seasonID, r.GroupID)
+}
+
+// SetSeasonRoster replaces the roster of a season with the given players. It
+// returns sql.ErrNoRows if the season is not one of the group's and
+// ErrUnknownPlayer if a player is not.
+func (r *Repository) SetSeasonRoster(seasonID int, playerIDs []int) error {
+	if _, err := r.GetSeason(seasonID); err != nil {
+		return err
+	}
+	if len(playerIDs) > 0 {
+		if err := r.checkPlayersExist(playerIDs); err != nil {
+			return err
+		}
+	}
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM season_players WHERE season_id = ?", seasonID); err != nil {
+		return err
+	}
+
+	for _, playerID := range playerIDs {
+		if err := addToRoster(tx, seasonID, playerID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// CopyPreviousRoster adds the active players of the previous season's roster
+// to the roster of the given season. Players already on the roster are kept.
+// It returns sql.ErrNoRows if the season is not one of the group's or has no
+// previous season.
+func (r *Repository) CopyPreviousRoster(seasonID int) error {
+	if _, err := r.GetSeason(seasonID); err != nil {
+		return err
+	}
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := copyPreviousRoster(tx, seasonID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// copyPreviousRoster copies the roster of the season of the same group
+// created before the given one. It returns sql.ErrNoRows if there is no
+// previous season.
+func copyPreviousRoster(tx *sql.Tx, seasonID int) error {
+	var previousID int
+	query := `
+		SELECT id FROM seasons
+		WHERE id < ? AND group_id = (SELECT group_id FROM seasons WHERE id = ?)
+		ORDER BY id DESC
+		LIMIT 1
+	`
+	if err := tx.QueryRow(query, seasonID, seasonID).Scan(&previousID); err != nil {
+		return err
+	}
+	return copyRoster(tx, previousID, seasonID)
+}
+
+// copyRoster adds the active players of one season's roster to the roster of
+// another
+func copyRoster(tx *sql.Tx, fromSeasonID, toSeasonID int) error {
+	query := `
+		INSERT IGNORE INTO season_players (season_id, player_id)
+		SELECT ?, sp.player_id
+		FROM season_players sp
+		JOIN players p ON p.id = sp.player_id
+		WHERE sp.season_id = ? AND p.active
+	`
+	_, err := tx.Exec(query, toSeasonID, fromSeasonID)
+	return err
+}
+
+// addToRoster adds a single player to the roster of a season
+func addToRoster(tx *sql.Tx, seasonID, playerID int) error {
+	query := "INSERT IGNORE INTO season_players (season_id, player_id) VALUES (?, ?)"
+	_, err := tx.Exec(query, seasonID, playerID)
+	return err
+}
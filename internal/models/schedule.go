@@ -0,0 +1,195 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// PlannedGame is a host and date of a game that has not been played yet
+type PlannedGame struct {
+	HostID   int       `json:"host_id"`
+	GameDate time.Time `json:"game_date"`
+}
+
+// Hosting is a played game seen as a player's turn to host
+type Hosting struct {
+	HostID     int       `json:"host_id"`
+	SeasonID   int       `json:"season_id"`
+	SeasonName string    `json:"season_name"`
+	GameDate   time.Time `json:"game_date"`
+}
+
+// GetHostings returns who hosted the played games of all seasons of the
+// group, oldest first
+func (r *Repository) GetHostings() ([]Hosting, error) {
+	query := `
+		SELECT g.host_id, g.season_id, s.name, g.game_date
+		FROM games g
+		JOIN seasons s ON g.season_id = s.id
+		WHERE g.status = 'played' AND s.group_id = ?
+		ORDER BY g.game_date, g.id
+	`
+	rows, err := r.DB.Query(query, r.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hostings []Hosting
+	for rows.Next() {
+		var h Hosting
+		if err := rows.Scan(&h.HostID, &h.SeasonID, &h.SeasonName, &h.GameDate); err != nil {
+			return nil, err
+		}
+		hostings = append(hostings, h)
+	}
+
+	return hostings, nil
+}
+
+// GetPastHostPositions returns, per host, the relative positions at which
+// they hosted in the group's seasons before the given one: 0 for the first
+// game of a season and 1 for the last
+func (r *Repository) GetPastHostPositions(seasonID int) (map[int][]float64, error) {
+	query := `
+		SELECT g.season_id, g.host_id
+		FROM games g
+		WHERE g.season_id < ? AND g.status = 'played' AND ` + gameInGroup + `
+		ORDER BY g.season_id, g.game_date, g.id
+	`
+	rows, err := r.DB.Query(query, seasonID, r.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bySeason := make(map[int][]int)
+	var seasonIDs []int
+	for rows.Next() {
+		var season, host int
+		if err := rows.Scan(&season, &host); err != nil {
+			return nil, err
+		}
+		if _, ok := bySeason[season]; !ok {
+			seasonIDs = append(seasonIDs, season)
+		}
+		bySeason[season] = append(bySeason[season], host)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	positions := make(map[int][]float64)
+	for _, season := range seasonIDs {
+		hosts := bySeason[season]
+		for i, host := range hosts {
+			position := 0.5
+			if len(hosts) > 1 {
+				position = float64(i) / float64(len(hosts)-1)
+			}
+			positions[host] = append(positions[host], position)
+		}
+	}
+
+	return positions, nil
+}
+
+// SaveSchedule stores a schedule of planned games for a season, replacing
+// the previous one, and returns the number of games it cancelled. Hosts who
+// already have an upcoming or cancelled game keep that game with its new
+// date, so its ID stays the same; upcoming games of hosts missing from the
+// schedule are cancelled.
+func (r *Repository) SaveSchedule(seasonID int, games []PlannedGame) (int, error) {
+	season, err := r.GetSeason(seasonID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrUnknownSeason
+	}
+	if err != nil {
+		return 0, err
+	}
+	if season.IsArchived() {
+		return 0, ErrSeasonArchived
+	}
+
+	hostIDs := make([]int, len(games))
+	for i, g := range games {
+		if !season.Contains(g.GameDate) {
+			return 0, ErrDateOutsideSeason
+		}
+		hostIDs[i] = g.HostID
+	}
+	if len(hostIDs) > 0 {
+		if err := r.checkPlayersExist(hostIDs); err != nil {
+			return 0, err
+		}
+	}
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Existing games that have not been played, by host
+	rows, err := tx.Query("SELECT id, host_id, status FROM games WHERE season_id = ? FOR UPDATE", seasonID)
+	if err != nil {
+		return 0, err
+	}
+	existing := make(map[int]Game)
+	for rows.Next() {
+		var g Game
+		if err := rows.Scan(&g.ID, &g.HostID, &g.Status); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		existing[g.HostID] = g
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	scheduled := make(map[int]bool, len(games))
+	for _, planned := range games {
+		if scheduled[planned.HostID] {
+			return 0, ErrDuplicateHost
+		}
+		scheduled[planned.HostID] = true
+
+		g, ok := existing[planned.HostID]
+		switch {
+		case !ok:
+			query := "INSERT INTO games (season_id, host_id, game_date, status) VALUES (?, ?, ?, ?)"
+			if _, err := tx.Exec(query, seasonID, planned.HostID, planned.GameDate, GameStatusPlanned); err != nil {
+				return 0, translateGameError(err)
+			}
+		case g.Status == GameStatusPlayed:
+			return 0, ErrDuplicateHost
+		default:
+			if err := changeGameDate(tx, g.ID, planned.GameDate); err != nil {
+				return 0, err
+			}
+			if _, err := tx.Exec("UPDATE games SET status = ?, revision = revision + 1 WHERE id = ?", GameStatusPlanned, g.ID); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	// Games of hosts missing from the schedule are cancelled rather than
+	// removed, so their date history and answers are kept
+	cancelled := 0
+	for hostID, g := range existing {
+		if !scheduled[hostID] && g.IsUpcoming() {
+			if err := transitionGameTx(tx, g.ID, GameStatusCancelled, nil); err != nil {
+				return 0, err
+			}
+			cancelled++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return cancelled, nil
+}
@@ -0,0 +1,317 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Season errors
+var (
+	// ErrUpcomingGames is returned when a season cannot be closed because
+	// games are still planned or postponed in it
+	ErrUpcomingGames = errors.New("season still has upcoming games")
+
+	ErrInvalidSeasonDates = errors.New("season cannot end before it starts")
+)
+
+// seasonNames are the names of the seasons of a year, starting in spring. A
+// winter belongs to the year it starts in, so Winter 2024 is followed by
+// Spring 2025.
+var seasonNames = []string{"Spring", "Summer", "Fall", "Winter"}
+
+// GetSeason returns a single season of the group by ID
+func (r *Repository) GetSeason(seasonID int) (Season, error) {
+	var s Season
+	query := "SELECT id, name, start_date, end_date, status, created_at FROM seasons WHERE id = ? AND group_id = ?"
+	err := r.DB.QueryRow(query, seasonID, r.GroupID).Scan(&s.ID, &s.Name, &s.StartDate, &s.EndDate, &s.Status, &s.CreatedAt)
+	return s, err
+}
+
+// GetCurrentSeason returns the active season of the group, see CurrentSeason.
+// It returns sql.ErrNoRows if all seasons are archived.
+func (r *Repository) GetCurrentSeason() (Season, error) {
+	seasons, err := r.queryActiveSeasons(r.DB, "")
+	if err != nil {
+		return Season{}, err
+	}
+	current, ok := CurrentSeason(seasons, time.Now())
+	if !ok {
+		return Season{}, sql.ErrNoRows
+	}
+	return current, nil
+}
+
+// CurrentSeason picks the current season among seasons: the active one whose
+// date range contains today, then the one that started last, then the one
+// created last. It returns false if no season is active.
+func CurrentSeason(seasons []Season, today time.Time) (Season, bool) {
+	var current Season
+	found := false
+	for _, s := range seasons {
+		if s.IsArchived() {
+			continue
+		}
+		if !found || seasonIsMoreCurrent(s, current, today) {
+			current = s
+			found = true
+		}
+	}
+	return current, found
+}
+
+// seasonIsMoreCurrent reports whether a comes before b in the order of
+// CurrentSeason. Seasons without a start date come after those with one.
+func seasonIsMoreCurrent(a, b Season, today time.Time) bool {
+	if a.Contains(today) != b.Contains(today) {
+		return a.Contains(today)
+	}
+	if (a.StartDate == nil) != (b.StartDate == nil) {
+		return a.StartDate != nil
+	}
+	if a.StartDate != nil && !a.StartDate.Equal(*b.StartDate) {
+		return a.StartDate.After(*b.StartDate)
+	}
+	return a.ID > b.ID
+}
+
+// seasonQuerier runs queries on the database or within a transaction
+type seasonQuerier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// queryActiveSeasons returns the active seasons of the group. suffix is
+// appended to the query, e.g. to lock the rows with FOR UPDATE.
+func (r *Repository) queryActiveSeasons(db seasonQuerier, suffix string) ([]Season, error) {
+	query := "SELECT id, name, start_date, end_date, status, created_at FROM seasons WHERE group_id = ? AND status = ? " + suffix
+	rows, err := db.Query(query, r.GroupID, SeasonStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seasons []Season
+	for rows.Next() {
+		var s Season
+		if err := rows.Scan(&s.ID, &s.Name, &s.StartDate, &s.EndDate, &s.Status, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		seasons = append(seasons, s)
+	}
+	return seasons, rows.Err()
+}
+
+// CreateSeason adds a new active season to the group and returns its ID. The
+// start and end date are optional.
+func (r *Repository) CreateSeason(name string, startDate, endDate *time.Time) (int, error) {
+	if startDate != nil && endDate != nil && endDate.Before(*startDate) {
+		return 0, ErrInvalidSeasonDates
+	}
+
+	query := "INSERT INTO seasons (group_id, name, start_date, end_date, status) VALUES (?, ?, ?, ?, ?)"
+	result, err := r.DB.Exec(query, r.GroupID, name, startDate, endDate, SeasonStatusActive)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// NextSeasonName returns the name of the season after the one with the given
+// name, e.g. "Spring 2025" after "Winter 2024". If the name does not follow
+// that pattern, it returns the name of the season that now falls into.
+func NextSeasonName(previous string, now time.Time) string {
+	if fields := strings.Fields(previous); len(fields) == 2 {
+		year, err := strconv.Atoi(fields[1])
+		for i, name := range seasonNames {
+			if err != nil || !strings.EqualFold(fields[0], name) {
+				continue
+			}
+			if i == len(seasonNames)-1 {
+				return fmt.Sprintf("%s %d", seasonNames[0], year+1)
+			}
+			return fmt.Sprintf("%s %d", seasonNames[i+1], year)
+		}
+	}
+
+	// March to May is spring, and so on; January and February still belong
+	// to the winter of the previous year
+	month := int(now.Month())
+	year := now.Year()
+	if month < 3 {
+		year--
+	}
+	return fmt.Sprintf("%s %d", seasonNames[(month+9)%12/3], year)
+}
+
+// StartNextSeason closes the current season of the group, see CurrentSeason,
+// and starts the one after it in a single transaction. The new season gets
+// its name from NextSeasonName and the roster of the current season, and
+// starts today. The current season is archived; if it has no end date, it
+// ends with its last game. It returns ErrUpcomingGames if the current season
+// still has games to play.
+func (r *Repository) StartNextSeason() (Season, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return Season{}, err
+	}
+	defer tx.Rollback()
+
+	// The active seasons stay locked until the new one is in place
+	active, err := r.queryActiveSeasons(tx, "FOR UPDATE")
+	if err != nil {
+		return Season{}, err
+	}
+	previous, _ := CurrentSeason(active, time.Now())
+
+	if previous.ID != 0 {
+		if err := checkNoUpcomingGames(tx, previous.ID); err != nil {
+			return Season{}, err
+		}
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	next := Season{Name: NextSeasonName(previous.Name, now), StartDate: &today, Status: SeasonStatusActive}
+	query := "INSERT INTO seasons (group_id, name, start_date, status) VALUES (?, ?, ?, ?)"
+	result, err := tx.Exec(query, r.GroupID, next.Name, next.StartDate, next.Status)
+	if err != nil {
+		return Season{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return Season{}, err
+	}
+	next.ID = int(id)
+
+	if previous.ID != 0 {
+		if err := copyRoster(tx, previous.ID, next.ID); err != nil {
+			return Season{}, err
+		}
+
+		end := previous.EndDate
+		if end == nil {
+			var last *time.Time
+			query = "SELECT MAX(game_date) FROM games WHERE season_id = ?"
+			if err := tx.QueryRow(query, previous.ID).Scan(&last); err != nil {
+				return Season{}, err
+			}
+			end = last
+			if end == nil || (previous.StartDate != nil && end.Before(*previous.StartDate)) {
+				end = previous.StartDate
+			}
+		}
+
+		query = "UPDATE seasons SET end_date = ?, status = ? WHERE id = ?"
+		if _, err := tx.Exec(query, end, SeasonStatusArchived, previous.ID); err != nil {
+			return Season{}, err
+		}
+	}
+
+	return next, tx.Commit()
+}
+
+// RenameSeason changes the name of a season
+func (r *Repository) RenameSeason(seasonID int, name string) error {
+	query := "UPDATE seasons SET name = ? WHERE id = ? AND group_id = ?"
+	return r.execAffectingOne(query, name, seasonID, r.GroupID)
+}
+
+// SetSeasonDates changes the date range of a season. Either date may be nil
+// to leave that side open. It returns ErrDateOutsideSeason if a game of the
+// season would fall outside the new range.
+func (r *Repository) SetSeasonDates(seasonID int, startDate, endDate *time.Time) error {
+	if startDate != nil && endDate != nil && endDate.Before(*startDate) {
+		return ErrInvalidSeasonDates
+	}
+
+	if _, err := r.GetSeason(seasonID); err != nil {
+		return err
+	}
+
+	rows, err := r.DB.Query("SELECT game_date FROM games WHERE season_id = ?", seasonID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	season := Season{StartDate: startDate, EndDate: endDate}
+	for rows.Next() {
+		var date time.Time
+		if err := rows.Scan(&date); err != nil {
+			return err
+		}
+		if !season.Contains(date) {
+			return ErrDateOutsideSeason
+		}
+	}
+
+	query := "UPDATE seasons SET start_date = ?, end_date = ? WHERE id = ? AND group_id = ?"
+	return r.execAffectingOne(query, startDate, endDate, seasonID, r.GroupID)
+}
+
+// ArchiveSeason marks a season as archived, which makes it read-only. It
+// returns ErrUpcomingGames if the season still has games to play.
+func (r *Repository) ArchiveSeason(seasonID int) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int
+	query := "SELECT id FROM seasons WHERE id = ? AND group_id = ? FOR UPDATE"
+	if err := tx.QueryRow(query, seasonID, r.GroupID).Scan(&id); err != nil {
+		return err
+	}
+
+	if err := checkNoUpcomingGames(tx, seasonID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE seasons SET status = ? WHERE id = ?", SeasonStatusArchived, seasonID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkNoUpcomingGames returns ErrUpcomingGames if games of the season are
+// still planned or postponed
+func checkNoUpcomingGames(tx *sql.Tx, seasonID int) error {
+	var upcoming int
+	query := "SELECT COUNT(*) FROM games WHERE season_id = ? AND status IN (?, ?)"
+	if err := tx.QueryRow(query, seasonID, GameStatusPlanned, GameStatusPostponed).Scan(&upcoming); err != nil {
+		return err
+	}
+	if upcoming > 0 {
+		return ErrUpcomingGames
+	}
+	return nil
+}
+
+// execAffectingOne runs an UPDATE or DELETE statement for a single row and
+// returns sql.ErrNoRows when no row matched. It relies on the connection
+// reporting matched rather than changed rows (clientFoundRows).
+func (r *Repository) execAffectingOne(query string, args ...interface{}) error {
+	result, err := r.DB.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
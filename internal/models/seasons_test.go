@@ -0,0 +1,125 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextSeasonName(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		previous string
+		now      time.Time
+		expected string
+	}{
+		{"Winter 2024", now, "Spring 2025"},
+		{"Spring 2025", now, "Summer 2025"},
+		{"Summer 2025", now, "Fall 2025"},
+		{"Fall 2025", now, "Winter 2025"},
+		{"winter 2025", now, "Spring 2026"},
+		{"Season One", now, "Fall 2026"},
+		{"Winter twenty", now, "Fall 2026"},
+		{"", time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC), "Winter 2025"},
+		{"", time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), "Spring 2026"},
+		{"", time.Date(2026, time.August, 31, 0, 0, 0, 0, time.UTC), "Summer 2026"},
+		{"", time.Date(2026, time.December, 1, 0, 0, 0, 0, time.UTC), "Winter 2026"},
+	}
+
+	for _, tt := range tests {
+		if got := NextSeasonName(tt.previous, tt.now); got != tt.expected {
+			t.Errorf("Expected %q after %q, got %q", tt.expected, tt.previous, got)
+		}
+	}
+}
+
+func TestSeasonContains(t *testing.T) {
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.May, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		season   Season
+		date     time.Time
+		expected bool
+	}{
+		{Season{StartDate: &start, EndDate: &end}, start, true},
+		{Season{StartDate: &start, EndDate: &end}, end, true},
+		{Season{StartDate: &start, EndDate: &end}, time.Date(2025, time.May, 31, 20, 0, 0, 0, time.UTC), true},
+		{Season{StartDate: &start, EndDate: &end}, time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC), false},
+		{Season{StartDate: &start, EndDate: &end}, time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC), false},
+		{Season{StartDate: &start}, time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC), true},
+		{Season{EndDate: &end}, time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC), true},
+		{Season{}, start, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.season.Contains(tt.date); got != tt.expected {
+			t.Errorf("Expected Contains(%s) to be %v for %q, got %v",
+				tt.date.Format("2006-01-02"), tt.expected, tt.season.DateRange(), got)
+		}
+	}
+}
+
+func TestSeasonDateRange(t *testing.T) {
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.May, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]Season{
+		"Mar 01, 2025 – May 31, 2025": {StartDate: &start, EndDate: &end},
+		"since Mar 01, 2025":          {StartDate: &start},
+		"until May 31, 2025":          {EndDate: &end},
+		"":                            {},
+	}
+
+	for expected, season := range tests {
+		if got := season.DateRange(); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+}
+
+func TestCurrentSeason(t *testing.T) {
+	today := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)
+	date := func(month time.Month, day int) *time.Time {
+		d := time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	tests := []struct {
+		seasons  []Season
+		expected int
+	}{
+		// Created later but started earlier: the later start wins, not the higher ID
+		{[]Season{
+			{ID: 1, Status: SeasonStatusActive, StartDate: date(time.September, 1)},
+			{ID: 2, Status: SeasonStatusActive, StartDate: date(time.March, 1)},
+		}, 1},
+		// A season containing today comes before one that started later
+		{[]Season{
+			{ID: 1, Status: SeasonStatusActive, StartDate: date(time.September, 1), EndDate: date(time.November, 30)},
+			{ID: 2, Status: SeasonStatusActive, StartDate: date(time.December, 1)},
+		}, 1},
+		// Archived seasons are never current
+		{[]Season{
+			{ID: 1, Status: SeasonStatusActive, StartDate: date(time.March, 1)},
+			{ID: 2, Status: SeasonStatusArchived, StartDate: date(time.September, 1)},
+		}, 1},
+		// Seasons without a start date come last, then the higher ID wins
+		{[]Season{
+			{ID: 3, Status: SeasonStatusActive},
+			{ID: 1, Status: SeasonStatusActive, StartDate: date(time.March, 1)},
+		}, 1},
+		{[]Season{
+			{ID: 2, Status: SeasonStatusActive},
+			{ID: 3, Status: SeasonStatusActive},
+		}, 3},
+		{[]Season{{ID: 1, Status: SeasonStatusArchived}}, 0},
+	}
+
+	for i, tt := range tests {
+		got, ok := CurrentSeason(tt.seasons, today)
+		if ok != (tt.expected != 0) || got.ID != tt.expected {
+			t.Errorf("Case %d: expected season %d, got %d (found %v)", i, tt.expected, got.ID, ok)
+		}
+	}
+}
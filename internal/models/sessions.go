@@ -0,0 +1,67 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+// sessionTokenBytes is the number of random bytes in a session token
+const sessionTokenBytes = 32
+
+// NewSessionToken returns a random token to identify a session in a cookie
+func NewSessionToken() (string, error) {
+	b := make([]byte, sessionTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashSessionToken returns the hash a session token is stored as, so that
+// the tokens themselves never end up in the database
+func HashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateSession logs a member in until the session expires and returns the
+// token identifying the session. Expired sessions of all members are removed.
+func (r *Repository) CreateSession(memberID int, ttl time.Duration) (string, error) {
+	token, err := NewSessionToken()
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := r.DB.Exec("DELETE FROM sessions WHERE expires_at <= NOW()"); err != nil {
+		return "", err
+	}
+
+	query := "INSERT INTO sessions (token_hash, member_id, expires_at) VALUES (?, ?, NOW() + INTERVAL ? SECOND)"
+	if _, err := r.DB.Exec(query, HashSessionToken(token), memberID, int(ttl.Seconds())); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// GetSessionMember returns the member logged in with a session token. It
+// returns sql.ErrNoRows if the session does not exist or has expired.
+func (r *Repository) GetSessionMember(token string) (Member, error) {
+	var m Member
+	query := `
+		SELECT m.id, m.email, m.name, m.password_hash, m.created_at
+		FROM sessions s
+		JOIN members m ON s.member_id = m.id
+		WHERE s.token_hash = ? AND s.expires_at > NOW()
+	`
+	err := r.DB.QueryRow(query, HashSessionToken(token)).Scan(&m.ID, &m.Email, &m.Name, &m.PasswordHash, &m.CreatedAt)
+	return m, err
+}
+
+// DeleteSession logs out the session with the given token
+func (r *Repository) DeleteSession(token string) error {
+	_, err := r.DB.Exec("DELETE FROM sessions WHERE token_hash = ?", HashSessionToken(token))
+	return err
+}
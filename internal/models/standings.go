@@ -0,0 +1,136 @@
+package models
+
+import (
+	"sort"
+)
+
+// PointsScheme defines how many points each placement is worth
+type PointsScheme struct {
+	Win    int `json:"win"`
+	Second int `json:"second"`
+}
+
+// Standing represents a player's position in the season leaderboard
+type Standing struct {
+	Rank     int    `json:"rank"`
+	PlayerID int    `json:"player_id"`
+	Name     string `json:"name"`
+	Points   int    `json:"points"`
+	Wins     int    `json:"wins"`
+	Seconds  int    `json:"seconds"`
+
+	// HeadToHead counts the games in which the player finished ahead of
+	// another player with the same points, wins and seconds
+	HeadToHead int `json:"head_to_head"`
+}
+
+// GetSeasonStandings returns the leaderboard for a given season
+func (r *Repository) GetSeasonStandings(seasonID int, scheme PointsScheme) ([]Standing, error) {
+	games, err := r.GetGames(seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	return computeStandings(PlayedGames(games), scheme), nil
+}
+
+// computeStandings tallies the points of every placed player and ranks them.
+// Ties are broken by most wins, then most second places, then head-to-head
+// results between the tied players. Players still tied after that share a rank
+// and are listed by name.
+func computeStandings(games []Game, scheme PointsScheme) []Standing {
+	byPlayer := make(map[int]*Standing)
+	get := func(id int, name string) *Standing {
+		s, ok := byPlayer[id]
+		if !ok {
+			s = &Standing{PlayerID: id, Name: name}
+			byPlayer[id] = s
+		}
+		return s
+	}
+
+	for _, g := range games {
+		if g.WinnerID != nil {
+			s := get(*g.WinnerID, g.WinnerName)
+			s.Wins++
+			s.Points += scheme.Win
+		}
+		if g.SecondPlaceID != nil {
+			s := get(*g.SecondPlaceID, g.SecondPlaceName)
+			s.Seconds++
+			s.Points += scheme.Second
+		}
+	}
+
+	standings := make([]Standing, 0, len(byPlayer))
+	for _, s := range byPlayer {
+		standings = append(standings, *s)
+	}
+
+	sameRecord := func(a, b Standing) bool {
+		return a.Points == b.Points && a.Wins == b.Wins && a.Seconds == b.Seconds
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		if a.Seconds != b.Seconds {
+			return a.Seconds > b.Seconds
+		}
+		return a.Name < b.Name
+	})
+
+	// Resolve remaining ties group by group using head-to-head results
+	for start := 0; start < len(standings); {
+		end := start + 1
+		for end < len(standings) && sameRecord(standings[start], standings[end]) {
+			end++
+		}
+		if end-start > 1 {
+			applyHeadToHead(standings[start:end], games)
+		}
+		start = end
+	}
+
+	for i := range standings {
+		if i > 0 && sameRecord(standings[i], standings[i-1]) && standings[i].HeadToHead == standings[i-1].HeadToHead {
+			standings[i].Rank = standings[i-1].Rank
+		} else {
+			standings[i].Rank = i + 1
+		}
+	}
+
+	return standings
+}
+
+// applyHeadToHead counts how often each player of a tied group finished ahead
+// of another member of the group and reorders the group accordingly
+func applyHeadToHead(group []Standing, games []Game) {
+	inGroup := make(map[int]bool, len(group))
+	for _, s := range group {
+		inGroup[s.PlayerID] = true
+	}
+
+	ahead := make(map[int]int)
+	for _, g := range games {
+		if g.WinnerID == nil || g.SecondPlaceID == nil {
+			continue
+		}
+		if inGroup[*g.WinnerID] && inGroup[*g.SecondPlaceID] {
+			ahead[*g.WinnerID]++
+		}
+	}
+
+	for i := range group {
+		group[i].HeadToHead = ahead[group[i].PlayerID]
+	}
+
+	sort.SliceStable(group, func(i, j int) bool {
+		return group[i].HeadToHead > group[j].HeadToHead
+	})
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"testing"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func placedGame(winnerID int, winnerName string, secondID int, secondName string) Game {
+	return Game{
+		WinnerID:        intPtr(winnerID),
+		WinnerName:      winnerName,
+		SecondPlaceID:   intPtr(secondID),
+		SecondPlaceName: secondName,
+	}
+}
+
+func TestComputeStandingsPoints(t *testing.T) {
+	games := []Game{
+		placedGame(1, "Alice", 2, "Bob"),
+		placedGame(1, "Alice", 3, "Charlie"),
+		placedGame(2, "Bob", 1, "Alice"),
+	}
+
+	standings := computeStandings(games, PointsScheme{Win: 3, Second: 1})
+
+	if len(standings) != 3 {
+		t.Fatalf("Expected 3 standings, got %d", len(standings))
+	}
+
+	expected := []struct {
+		name   string
+		points int
+		rank   int
+	}{
+		{"Alice", 7, 1},
+		{"Bob", 4, 2},
+		{"Charlie", 1, 3},
+	}
+	for i, e := range expected {
+		s := standings[i]
+		if s.Name != e.name || s.Points != e.points || s.Rank != e.rank {
+			t.Errorf("Position %d: expected %s with %d points at rank %d, got %s with %d points at rank %d",
+				i, e.name, e.points, e.rank, s.Name, s.Points, s.Rank)
+		}
+	}
+}
+
+func TestComputeStandingsTieBreakers(t *testing.T) {
+	// With 2 points for a win and 1 for second, Bob (1 win) and Charlie
+	// (2 seconds) both have 2 points; Bob ranks ahead on wins.
+	games := []Game{
+		placedGame(2, "Bob", 4, "Dave"),
+		placedGame(4, "Dave", 3, "Charlie"),
+		placedGame(5, "Eve", 3, "Charlie"),
+	}
+
+	standings := computeStandings(games, PointsScheme{Win: 2, Second: 1})
+
+	order := make([]string, len(standings))
+	for i, s := range standings {
+		order[i] = s.Name
+	}
+
+	want := []string{"Dave", "Bob", "Eve", "Charlie"}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("Expected order %v, got %v", want, order)
+		}
+	}
+}
+
+func TestComputeStandingsHeadToHead(t *testing.T) {
+	// Bob and Alice have identical records, but Bob beat Alice directly
+	games := []Game{
+		placedGame(2, "Bob", 1, "Alice"),
+		placedGame(1, "Alice", 4, "Dave"),
+		placedGame(5, "Eve", 2, "Bob"),
+	}
+
+	standings := computeStandings(games, PointsScheme{Win: 3, Second: 1})
+
+	if standings[0].Name != "Bob" {
+		t.Errorf("Expected Bob to lead on head-to-head, got %s", standings[0].Name)
+	}
+	if standings[0].Rank != 1 || standings[1].Rank != 2 {
+		t.Errorf("Expected head-to-head to separate ranks, got %d and %d", standings[0].Rank, standings[1].Rank)
+	}
+}
+
+func TestComputeStandingsSharedRank(t *testing.T) {
+	games := []Game{
+		placedGame(2, "Bob", 3, "Charlie"),
+		placedGame(1, "Alice", 4, "Dave"),
+	}
+
+	standings := computeStandings(games, PointsScheme{Win: 3, Second: 1})
+
+	if standings[0].Name != "Alice" || standings[1].Name != "Bob" {
+		t.Fatalf("Expected fully tied players to be ordered by name, got %s and %s", standings[0].Name, standings[1].Name)
+	}
+	if standings[0].Rank != 1 || standings[1].Rank != 1 {
+		t.Errorf("Expected fully tied players to share rank 1, got %d and %d", standings[0].Rank, standings[1].Rank)
+	}
+	if standings[2].Rank != 3 {
+		t.Errorf("Expected next player to be ranked 3, got %d", standings[2].Rank)
+	}
+}
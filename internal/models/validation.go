@@ -0,0 +1,215 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Errors returned when a game does not pass validation
+var (
+	ErrUnknownSeason     = errors.New("season does not exist")
+	ErrUnknownPlayer     = errors.New("player does not exist")
+	ErrUnknownGame       = errors.New("game does not exist")
+	ErrSeasonArchived    = errors.New("season is archived")
+	ErrDateOutsideSeason = errors.New("date is outside the season")
+	ErrDuplicateHost     = errors.New("host has already hosted a game this season")
+	ErrSamePlayerTwice   = errors.New("the same player is listed twice")
+	ErrUnbalancedLedger  = errors.New("payouts must equal the pot of buy-ins and rebuys")
+)
+
+// MySQL error numbers mapped to validation errors
+const (
+	mysqlDuplicateEntry  = 1062
+	mysqlNoReferencedRow = 1452
+	mysqlCheckViolated   = 3819
+)
+
+// Constraints on games, their results and ledger whose violations are
+// reported as validation errors
+var gameConstraintErrors = map[string]error{
+	"unique_season_host":        ErrDuplicateHost,
+	"unique_game_player":        ErrSamePlayerTwice,
+	"unique_game_ledger_player": ErrSamePlayerTwice,
+	"check_winner_not_second":   ErrSamePlayerTwice,
+}
+
+// ValidateResults checks a finishing order and ledger on their own: no player
+// may be placed or booked twice and the payouts must equal the pot
+func ValidateResults(finishingOrder []int, ledger []LedgerEntry) error {
+	placed := make(map[int]bool, len(finishingOrder))
+	for _, playerID := range finishingOrder {
+		if placed[playerID] {
+			return ErrSamePlayerTwice
+		}
+		placed[playerID] = true
+	}
+
+	booked := make(map[int]bool, len(ledger))
+	for _, e := range ledger {
+		if booked[e.PlayerID] {
+			return ErrSamePlayerTwice
+		}
+		booked[e.PlayerID] = true
+	}
+
+	if !LedgerIsBalanced(ledger) {
+		return ErrUnbalancedLedger
+	}
+
+	return nil
+}
+
+// ValidateGame checks a game before it is added (gameID 0) or updated. The
+// season must exist and be open, the date must fall within the season, all
+// players must exist and the host must not have hosted another game in the
+// season.
+func (r *Repository) ValidateGame(gameID, seasonID, hostID int, gameDate time.Time, finishingOrder []int, ledger []LedgerEntry) error {
+	if err := ValidateResults(finishingOrder, ledger); err != nil {
+		return err
+	}
+
+	season, err := r.GetSeason(seasonID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUnknownSeason
+	}
+	if err != nil {
+		return err
+	}
+	if season.IsArchived() {
+		return ErrSeasonArchived
+	}
+	if !season.Contains(gameDate) {
+		return ErrDateOutsideSeason
+	}
+
+	playerIDs := append([]int{hostID}, finishingOrder...)
+	for _, e := range ledger {
+		playerIDs = append(playerIDs, e.PlayerID)
+	}
+	if err := r.checkPlayersExist(playerIDs); err != nil {
+		return err
+	}
+
+	var hosted int
+	query := "SELECT COUNT(*) FROM games WHERE season_id = ? AND host_id = ? AND id <> ?"
+	if err := r.DB.QueryRow(query, seasonID, hostID, gameID).Scan(&hosted); err != nil {
+		return err
+	}
+	if hosted > 0 {
+		return ErrDuplicateHost
+	}
+
+	return nil
+}
+
+// checkGameEditable checks that a game of the group exists and its season is
+// not archived
+func (r *Repository) checkGameEditable(gameID int) error {
+	var status string
+	query := `
+		SELECT s.status
+		FROM games g
+		JOIN seasons s ON g.season_id = s.id
+		WHERE g.id = ? AND s.group_id = ?
+	`
+	err := r.DB.QueryRow(query, gameID, r.GroupID).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUnknownGame
+	}
+	if err != nil {
+		return err
+	}
+	if status == SeasonStatusArchived {
+		return ErrSeasonArchived
+	}
+	return nil
+}
+
+// checkGameDate returns ErrDateOutsideSeason if a date does not fall within
+// the season of a game
+func (r *Repository) checkGameDate(gameID int, date time.Time) error {
+	var season Season
+	query := `
+		SELECT s.start_date, s.end_date
+		FROM games g
+		JOIN seasons s ON g.season_id = s.id
+		WHERE g.id = ? AND s.group_id = ?
+	`
+	err := r.DB.QueryRow(query, gameID, r.GroupID).Scan(&season.StartDate, &season.EndDate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUnknownGame
+	}
+	if err != nil {
+		return err
+	}
+	if !season.Contains(date) {
+		return ErrDateOutsideSeason
+	}
+	return nil
+}
+
+// checkPlayersExist returns ErrUnknownPlayer if any of the IDs is not a
+// player of the group
+func (r *Repository) checkPlayersExist(playerIDs []int) error {
+	unique := make(map[int]bool, len(playerIDs))
+	args := make([]interface{}, 0, len(playerIDs))
+	for _, id := range playerIDs {
+		if !unique[id] {
+			unique[id] = true
+			args = append(args, id)
+		}
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+	query := "SELECT COUNT(*) FROM players WHERE group_id = ? AND id IN (" + placeholders + ")"
+
+	var found int
+	if err := r.DB.QueryRow(query, append([]interface{}{r.GroupID}, args...)...).Scan(&found); err != nil {
+		return err
+	}
+	if found != len(args) {
+		return ErrUnknownPlayer
+	}
+	return nil
+}
+
+// translateGameError maps constraint violations raised while writing a game
+// to the matching validation errors. This catches what slips past
+// ValidateGame, such as two submissions of the same game at once. Violations
+// of other constraints are returned unchanged.
+func translateGameError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return err
+	}
+
+	switch mysqlErr.Number {
+	case mysqlDuplicateEntry, mysqlCheckViolated:
+		if translated, ok := gameConstraintErrors[violatedConstraint(mysqlErr.Message)]; ok {
+			return translated
+		}
+	case mysqlNoReferencedRow:
+		return ErrUnknownPlayer
+	}
+	return err
+}
+
+// violatedConstraint returns the name of the key or check constraint in a
+// MySQL error message, which MySQL quotes last, e.g. "Duplicate entry '3-7'
+// for key 'games.unique_season_host'"
+func violatedConstraint(message string) string {
+	end := strings.LastIndex(message, "'")
+	if end < 0 {
+		return ""
+	}
+	start := strings.LastIndex(message[:end], "'")
+	if start < 0 {
+		return ""
+	}
+	name := message[start+1 : end]
+	return name[strings.LastIndex(name, ".")+1:]
+}
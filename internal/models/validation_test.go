@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestValidateResults(t *testing.T) {
+	balanced := []LedgerEntry{
+		{PlayerID: 1, BuyIn: 1000, Payout: 1500},
+		{PlayerID: 2, BuyIn: 1000, Rebuys: 500, Payout: 1000},
+	}
+
+	cases := map[string]struct {
+		finishingOrder []int
+		ledger         []LedgerEntry
+		want           error
+	}{
+		"empty":               {nil, nil, nil},
+		"valid":               {[]int{1, 2, 3}, balanced, nil},
+		"winner is second":    {[]int{1, 1}, nil, ErrSamePlayerTwice},
+		"player placed twice": {[]int{1, 2, 3, 2}, nil, ErrSamePlayerTwice},
+		"player booked twice": {[]int{1, 2}, append(balanced, LedgerEntry{PlayerID: 1}), ErrSamePlayerTwice},
+		"unbalanced":          {[]int{1, 2}, balanced[:1], ErrUnbalancedLedger},
+	}
+
+	for name, c := range cases {
+		if err := ValidateResults(c.finishingOrder, c.ledger); !errors.Is(err, c.want) {
+			t.Errorf("%s: expected %v, got %v", name, c.want, err)
+		}
+	}
+}
+
+func TestTranslateGameError(t *testing.T) {
+	other := errors.New("connection lost")
+
+	cases := map[string]struct {
+		err  error
+		want error
+	}{
+		"host twice":          {&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '3-7' for key 'games.unique_season_host'"}, ErrDuplicateHost},
+		"host twice, old key": {&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '3-7' for key 'unique_season_host'"}, ErrDuplicateHost},
+		"player placed twice": {&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '12-4' for key 'game_results.unique_game_player'"}, ErrSamePlayerTwice},
+		"winner is second":    {&mysql.MySQLError{Number: 3819, Message: "Check constraint 'check_winner_not_second' is violated."}, ErrSamePlayerTwice},
+		"unknown player":      {&mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}, ErrUnknownPlayer},
+		"other error":         {other, other},
+	}
+
+	for name, c := range cases {
+		if err := translateGameError(c.err); !errors.Is(err, c.want) {
+			t.Errorf("%s: expected %v, got %v", name, c.want, err)
+		}
+	}
+
+	// Violations of other constraints are returned unchanged
+	for _, err := range []*mysql.MySQLError{
+		{Number: 1062, Message: "Duplicate entry '12-2' for key 'game_results.unique_game_position'"},
+		{Number: 3819, Message: "Check constraint 'check_season_dates' is violated."},
+	} {
+		if got := translateGameError(err); got != error(err) {
+			t.Errorf("Expected %v unchanged, got %v", err, got)
+		}
+	}
+}
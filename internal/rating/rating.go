@@ -0,0 +1,166 @@
+// Package rating computes a multiplayer Elo rating from the finishing order
+// of games. Every game is treated as a set of head-to-head matches: each
+// player plays against everyone else at the table and wins against those who
+// finished below them.
+package rating
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Params configures the rating
+type Params struct {
+	// K is the most a player's rating can change in a single game
+	K float64
+
+	// Start is the rating of a player before their first game
+	Start float64
+}
+
+// Placing is a player's rank in a game. Players with the same rank tied.
+type Placing struct {
+	PlayerID int
+	Rank     int
+}
+
+// Game is a played game with the ranks of its players
+type Game struct {
+	ID       int
+	Date     time.Time
+	Placings []Placing
+}
+
+// Point is a player's rating after a game
+type Point struct {
+	GameID int
+	Date   time.Time
+	Rating float64
+	Change float64
+}
+
+// History holds the rating after every game of each player, oldest first
+type History map[int][]Point
+
+// Entry is a player's line in the ratings table
+type Entry struct {
+	PlayerID   int
+	Rating     float64
+	Peak       float64
+	Games      int
+	LastChange float64
+}
+
+// Replay rates the games in the order they were played, by date and then
+// by ID. Games with fewer than two players do not change any rating.
+func Replay(games []Game, p Params) History {
+	ordered := make([]Game, len(games))
+	copy(ordered, games)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if !ordered[i].Date.Equal(ordered[j].Date) {
+			return ordered[i].Date.Before(ordered[j].Date)
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	current := make(map[int]float64)
+	history := make(History)
+	for _, g := range ordered {
+		if len(g.Placings) < 2 {
+			continue
+		}
+
+		for _, pl := range g.Placings {
+			if _, ok := current[pl.PlayerID]; !ok {
+				current[pl.PlayerID] = p.Start
+			}
+		}
+
+		// All changes are based on the ratings before the game
+		changes := Changes(g.Placings, current, p.K)
+		for _, pl := range g.Placings {
+			current[pl.PlayerID] += changes[pl.PlayerID]
+			history[pl.PlayerID] = append(history[pl.PlayerID], Point{
+				GameID: g.ID,
+				Date:   g.Date,
+				Rating: current[pl.PlayerID],
+				Change: changes[pl.PlayerID],
+			})
+		}
+	}
+
+	return history
+}
+
+// Changes returns the rating change of every player in a game. The K-factor
+// is shared among the n-1 opponents, so a player who beats everyone gains at
+// most K. The changes of all players add up to zero.
+func Changes(placings []Placing, ratings map[int]float64, k float64) map[int]float64 {
+	changes := make(map[int]float64, len(placings))
+	n := len(placings)
+	if n < 2 {
+		return changes
+	}
+
+	for _, a := range placings {
+		var sum float64
+		for _, b := range placings {
+			if a.PlayerID == b.PlayerID {
+				continue
+			}
+			sum += score(a.Rank, b.Rank) - Expected(ratings[a.PlayerID], ratings[b.PlayerID])
+		}
+		changes[a.PlayerID] = k * sum / float64(n-1)
+	}
+
+	return changes
+}
+
+// Expected returns the chance of a player with rating a finishing above a
+// player with rating b
+func Expected(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// score is the outcome of a head-to-head match between two ranks: 1 for
+// finishing above, 0.5 for a tie and 0 for finishing below
+func score(rank, opponent int) float64 {
+	switch {
+	case rank < opponent:
+		return 1
+	case rank == opponent:
+		return 0.5
+	}
+	return 0
+}
+
+// Table returns the current rating of every rated player, highest first
+func (h History) Table() []Entry {
+	entries := make([]Entry, 0, len(h))
+	for playerID, points := range h {
+		if len(points) == 0 {
+			continue
+		}
+		last := points[len(points)-1]
+		e := Entry{
+			PlayerID:   playerID,
+			Rating:     last.Rating,
+			Peak:       last.Rating,
+			Games:      len(points),
+			LastChange: last.Change,
+		}
+		for _, pt := range points {
+			e.Peak = math.Max(e.Peak, pt.Rating)
+		}
+		entries = append(entries, e)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Rating != entries[j].Rating {
+			return entries[i].Rating > entries[j].Rating
+		}
+		return entries[i].PlayerID < entries[j].PlayerID
+	})
+	return entries
+}
@@ -0,0 +1,96 @@
+package rating
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var params = Params{K: 32, Start: 1500}
+
+func day(d int) time.Time {
+	return time.Date(2025, time.January, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestExpected(t *testing.T) {
+	if got := Expected(1500, 1500); got != 0.5 {
+		t.Errorf("Expected 0.5 for equal ratings, got %v", got)
+	}
+	if got := Expected(1900, 1500); math.Abs(got-0.909) > 0.001 {
+		t.Errorf("Expected about 0.909 for a 400 point lead, got %v", got)
+	}
+}
+
+func TestChangesTwoPlayers(t *testing.T) {
+	ratings := map[int]float64{1: 1500, 2: 1500}
+	changes := Changes([]Placing{{PlayerID: 1, Rank: 1}, {PlayerID: 2, Rank: 2}}, ratings, 32)
+
+	if changes[1] != 16 || changes[2] != -16 {
+		t.Errorf("Expected +16 and -16, got %v and %v", changes[1], changes[2])
+	}
+}
+
+func TestChangesSumToZero(t *testing.T) {
+	ratings := map[int]float64{1: 1620, 2: 1480, 3: 1500, 4: 1390}
+	placings := []Placing{
+		{PlayerID: 3, Rank: 1},
+		{PlayerID: 1, Rank: 2},
+		{PlayerID: 4, Rank: 3},
+		{PlayerID: 2, Rank: 3},
+	}
+	changes := Changes(placings, ratings, 32)
+
+	var sum float64
+	for _, c := range changes {
+		sum += c
+	}
+	if math.Abs(sum) > 1e-9 {
+		t.Errorf("Expected changes to sum to zero, got %v", sum)
+	}
+	if changes[3] <= 0 || changes[3] > 32 {
+		t.Errorf("Expected the winner to gain at most K, got %v", changes[3])
+	}
+	if changes[1] >= 0 {
+		t.Errorf("Expected the favourite to lose rating after finishing second, got %v", changes[1])
+	}
+}
+
+func TestReplay(t *testing.T) {
+	games := []Game{
+		// Given out of order; the second game is replayed first
+		{ID: 2, Date: day(8), Placings: []Placing{{PlayerID: 2, Rank: 1}, {PlayerID: 1, Rank: 2}}},
+		{ID: 1, Date: day(1), Placings: []Placing{{PlayerID: 1, Rank: 1}, {PlayerID: 2, Rank: 2}}},
+		{ID: 3, Date: day(9), Placings: []Placing{{PlayerID: 3, Rank: 1}}},
+	}
+	history := Replay(games, params)
+
+	first := history[1]
+	if len(first) != 2 || first[0].GameID != 1 || first[1].GameID != 2 {
+		t.Fatalf("Expected player 1 to be rated after games 1 and 2, got %v", first)
+	}
+	if first[0].Rating != 1516 {
+		t.Errorf("Expected 1516 after the first win, got %v", first[0].Rating)
+	}
+	if first[1].Change >= -16 {
+		t.Errorf("Expected the higher rated player to lose more than 16, got %v", first[1].Change)
+	}
+
+	if _, ok := history[3]; ok {
+		t.Errorf("Expected a game with a single player not to be rated")
+	}
+}
+
+func TestTable(t *testing.T) {
+	history := History{
+		1: {{Rating: 1520, Change: 20}, {Rating: 1505, Change: -15}},
+		2: {{Rating: 1480, Change: -20}, {Rating: 1510, Change: 30}},
+	}
+	table := history.Table()
+
+	if len(table) != 2 || table[0].PlayerID != 2 || table[1].PlayerID != 1 {
+		t.Fatalf("Expected player 2 ahead of player 1, got %v", table)
+	}
+	if table[1].Peak != 1520 || table[1].Games != 2 || table[1].LastChange != -15 {
+		t.Errorf("Expected peak 1520, 2 games and last change -15, got %+v", table[1])
+	}
+}
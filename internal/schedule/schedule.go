@@ -0,0 +1,145 @@
+// Package schedule proposes a host order and game dates for the rest of a
+// season. The host draw is random but weighted against the order of past
+// seasons, so the same people do not keep hosting first.
+package schedule
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Host is a player who still has to host a game
+type Host struct {
+	ID   int
+	Name string
+}
+
+// Slot is a proposed game: a host and a date
+type Slot struct {
+	Host Host
+	Date time.Time
+}
+
+// Cadence describes when games take place
+type Cadence struct {
+	// Start is the earliest possible game date
+	Start time.Time
+
+	// End is the latest possible game date; the zero time means open-ended
+	End time.Time
+
+	// Weekday is the day of the week games are played on
+	Weekday time.Weekday
+
+	// IntervalWeeks is the number of weeks between two games, e.g. 2 for
+	// every other week
+	IntervalWeeks int
+
+	// Blackouts are dates on which no game can take place
+	Blackouts []time.Time
+}
+
+// History holds, per host ID, the relative positions at which the host hosted
+// in past seasons, from 0 (first game of a season) to 1 (last game)
+type History map[int][]float64
+
+// Generate draws a host order and assigns each host a date from the cadence.
+// If the cadence ends before every host has a date, the hosts drawn last are
+// left out.
+func Generate(hosts []Host, history History, cadence Cadence, rng *rand.Rand) []Slot {
+	order := Order(hosts, history, rng)
+	dates := Dates(cadence, len(order))
+
+	slots := make([]Slot, len(dates))
+	for i, d := range dates {
+		slots[i] = Slot{Host: order[i], Date: d}
+	}
+	return slots
+}
+
+// Order shuffles the hosts at random, weighted so that hosts who hosted
+// early in past seasons tend to come later, and vice versa. Hosts without
+// history are treated as having hosted in the middle.
+func Order(hosts []Host, history History, rng *rand.Rand) []Host {
+	type weighted struct {
+		host Host
+		key  float64
+	}
+
+	// Weighted sampling without replacement: sorting by u^(1/w) draws hosts
+	// with a high weight earlier (Efraimidis and Spirakis)
+	draw := make([]weighted, len(hosts))
+	for i, h := range hosts {
+		w := weight(history[h.ID])
+		draw[i] = weighted{host: h, key: math.Pow(rng.Float64(), 1/w)}
+	}
+
+	sort.SliceStable(draw, func(i, j int) bool {
+		return draw[i].key > draw[j].key
+	})
+
+	order := make([]Host, len(draw))
+	for i, d := range draw {
+		order[i] = d.host
+	}
+	return order
+}
+
+// Dates returns n game dates following the cadence. A date that falls on a
+// blackout moves to the same weekday of the next free week, and the cadence
+// continues from there. Fewer dates are returned if the cadence ends first.
+func Dates(cadence Cadence, n int) []time.Time {
+	interval := cadence.IntervalWeeks
+	if interval < 1 {
+		interval = 1
+	}
+
+	blackout := make(map[string]bool, len(cadence.Blackouts))
+	for _, d := range cadence.Blackouts {
+		blackout[d.Format("2006-01-02")] = true
+	}
+
+	var end time.Time
+	if !cadence.End.IsZero() {
+		end = truncateDay(cadence.End)
+	}
+
+	start := truncateDay(cadence.Start)
+	offset := (int(cadence.Weekday) - int(start.Weekday()) + 7) % 7
+	date := start.AddDate(0, 0, offset)
+
+	dates := make([]time.Time, 0, n)
+	for len(dates) < n {
+		for blackout[date.Format("2006-01-02")] {
+			date = date.AddDate(0, 0, 7)
+		}
+		if !end.IsZero() && date.After(end) {
+			break
+		}
+		dates = append(dates, date)
+		date = date.AddDate(0, 0, 7*interval)
+	}
+	return dates
+}
+
+// weight returns the draw weight of a host from their past positions. It
+// ranges from 0.2 for a host who always hosted first to 1.8 for one who
+// always hosted last.
+func weight(positions []float64) float64 {
+	if len(positions) == 0 {
+		return 1
+	}
+
+	var sum float64
+	for _, p := range positions {
+		sum += p
+	}
+	return 0.2 + 1.6*sum/float64(len(positions))
+}
+
+// truncateDay returns midnight UTC of the given date
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
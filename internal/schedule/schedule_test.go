@@ -0,0 +1,129 @@
+package schedule
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestDates(t *testing.T) {
+	// 2025-03-03 is a Monday; games every other Friday
+	cadence := Cadence{
+		Start:         date("2025-03-03"),
+		Weekday:       time.Friday,
+		IntervalWeeks: 2,
+		Blackouts:     []time.Time{date("2025-03-21")},
+	}
+
+	got := Dates(cadence, 4)
+	want := []string{"2025-03-07", "2025-03-28", "2025-04-11", "2025-04-25"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d dates, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i].Format("2006-01-02") != want[i] {
+			t.Errorf("Date %d: expected %s, got %s", i, want[i], got[i].Format("2006-01-02"))
+		}
+	}
+}
+
+func TestDatesStartOnWeekday(t *testing.T) {
+	cadence := Cadence{Start: date("2025-03-07"), Weekday: time.Friday}
+
+	got := Dates(cadence, 2)
+	if got[0].Format("2006-01-02") != "2025-03-07" || got[1].Format("2006-01-02") != "2025-03-14" {
+		t.Errorf("Expected weekly dates from the start date, got %v", got)
+	}
+}
+
+func TestDatesStopAtEnd(t *testing.T) {
+	cadence := Cadence{
+		Start:     date("2025-03-07"),
+		End:       date("2025-03-21"),
+		Weekday:   time.Friday,
+		Blackouts: []time.Time{date("2025-03-14")},
+	}
+
+	got := Dates(cadence, 4)
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 dates, got %v", got)
+	}
+	if got[1].Format("2006-01-02") != "2025-03-21" {
+		t.Errorf("Expected the end date as last date, got %s", got[1].Format("2006-01-02"))
+	}
+}
+
+func TestGenerateLeavesOutHostsAfterEnd(t *testing.T) {
+	hosts := []Host{{1, "A"}, {2, "B"}, {3, "C"}}
+	cadence := Cadence{Start: date("2025-03-07"), End: date("2025-03-14"), Weekday: time.Friday}
+
+	slots := Generate(hosts, nil, cadence, rand.New(rand.NewSource(1)))
+	if len(slots) != 2 {
+		t.Fatalf("Expected 2 slots, got %d", len(slots))
+	}
+	if slots[0].Host.ID == slots[1].Host.ID {
+		t.Errorf("Expected different hosts, got %d twice", slots[0].Host.ID)
+	}
+}
+
+func TestOrderKeepsAllHosts(t *testing.T) {
+	hosts := []Host{{1, "A"}, {2, "B"}, {3, "C"}, {4, "D"}}
+
+	order := Order(hosts, nil, rand.New(rand.NewSource(1)))
+	if len(order) != len(hosts) {
+		t.Fatalf("Expected %d hosts, got %d", len(hosts), len(order))
+	}
+
+	seen := make(map[int]bool)
+	for _, h := range order {
+		if seen[h.ID] {
+			t.Errorf("Host %d drawn twice", h.ID)
+		}
+		seen[h.ID] = true
+	}
+}
+
+func TestOrderFavoursLateHosts(t *testing.T) {
+	hosts := []Host{{1, "Always first"}, {2, "Always last"}}
+	history := History{
+		1: {0, 0, 0},
+		2: {1, 1, 1},
+	}
+
+	rng := rand.New(rand.NewSource(42))
+	lastFirst := 0
+	for i := 0; i < 1000; i++ {
+		if Order(hosts, history, rng)[0].ID == 2 {
+			lastFirst++
+		}
+	}
+
+	// With weights 0.2 and 1.8, the former last host goes first 90% of the time
+	if lastFirst < 850 || lastFirst > 950 {
+		t.Errorf("Expected the former last host first in about 900 of 1000 draws, got %d", lastFirst)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	hosts := []Host{{1, "A"}, {2, "B"}, {3, "C"}}
+	cadence := Cadence{Start: date("2025-03-07"), Weekday: time.Friday, IntervalWeeks: 1}
+
+	slots := Generate(hosts, nil, cadence, rand.New(rand.NewSource(7)))
+	if len(slots) != 3 {
+		t.Fatalf("Expected 3 slots, got %d", len(slots))
+	}
+	for i, s := range slots {
+		want := date("2025-03-07").AddDate(0, 0, 7*i)
+		if !s.Date.Equal(want) {
+			t.Errorf("Slot %d: expected %s, got %s", i, want.Format("2006-01-02"), s.Date.Format("2006-01-02"))
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// Package settlement computes who pays whom to settle the net balances of a
+// game or a whole season with as few transfers as possible.
+package settlement
+
+import (
+	"sort"
+
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+// exactLimit is the largest number of non-zero balances for which the
+// minimal set of transfers is searched exhaustively. Above it, a greedy
+// matching is used, which needs at most one transfer less than the number
+// of players.
+const exactLimit = 16
+
+// Transfer is a single payment from a player who lost money to one who won
+type Transfer struct {
+	FromID   int          `json:"from_id"`
+	FromName string       `json:"from_name"`
+	ToID     int          `json:"to_id"`
+	ToName   string       `json:"to_name"`
+	Amount   models.Cents `json:"amount_cents"`
+}
+
+// Settle returns the transfers that bring all balances to zero. Balances are
+// expected to sum up to zero; any remainder is left unsettled.
+//
+// The number of transfers is minimal: the players are split into as many
+// groups with a zero sum as possible, and each group of n players is settled
+// with n-1 transfers.
+func Settle(balances []models.PlayerBalance) []Transfer {
+	var open []models.PlayerBalance
+	for _, b := range balances {
+		if b.Net != 0 {
+			open = append(open, b)
+		}
+	}
+
+	// Sort for deterministic results regardless of input order
+	sort.Slice(open, func(i, j int) bool {
+		return open[i].PlayerID < open[j].PlayerID
+	})
+
+	var transfers []Transfer
+	for _, group := range zeroSumGroups(open) {
+		transfers = append(transfers, settleGroup(group)...)
+	}
+
+	return transfers
+}
+
+// ApplyPayments returns the balances that remain after the given payments
+// have been made. A payment reduces the payer's debt and the receiver's credit.
+func ApplyPayments(balances []models.PlayerBalance, payments []models.SettlementPayment) []models.PlayerBalance {
+	index := make(map[int]int, len(balances))
+	remaining := make([]models.PlayerBalance, len(balances))
+	for i, b := range balances {
+		remaining[i] = b
+		index[b.PlayerID] = i
+	}
+
+	adjust := func(playerID int, name string, amount models.Cents) {
+		i, ok := index[playerID]
+		if !ok {
+			index[playerID] = len(remaining)
+			remaining = append(remaining, models.PlayerBalance{PlayerID: playerID, Name: name})
+			i = len(remaining) - 1
+		}
+		remaining[i].Net += amount
+	}
+
+	for _, p := range payments {
+		adjust(p.FromID, p.FromName, p.Amount)
+		adjust(p.ToID, p.ToName, -p.Amount)
+	}
+
+	return remaining
+}
+
+// zeroSumGroups partitions the balances into the largest possible number of
+// groups that each sum up to zero. The last group holds any remainder.
+func zeroSumGroups(balances []models.PlayerBalance) [][]models.PlayerBalance {
+	n := len(balances)
+	if n == 0 {
+		return nil
+	}
+	if n > exactLimit {
+		return [][]models.PlayerBalance{balances}
+	}
+
+	full := 1<<n - 1
+	sums := make([]models.Cents, full+1)
+	groups := make([]int, full+1)
+	for mask := 1; mask <= full; mask++ {
+		lowest := mask & -mask
+		sums[mask] = sums[mask^lowest] + balances[bitIndex(lowest)].Net
+
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) != 0 && groups[mask^(1<<i)] > groups[mask] {
+				groups[mask] = groups[mask^(1<<i)]
+			}
+		}
+		if sums[mask] == 0 {
+			groups[mask]++
+		}
+	}
+
+	// Walk back from the full set, removing one player at a time while
+	// keeping the best group count. Every zero-sum set on the way closes a group.
+	var result [][]models.PlayerBalance
+	mask, boundary := full, full
+	for mask != 0 {
+		next := 0
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) == 0 {
+				continue
+			}
+			candidate := mask ^ (1 << i)
+			want := groups[mask]
+			if sums[mask] == 0 {
+				want--
+			}
+			if groups[candidate] == want {
+				next = candidate
+				break
+			}
+		}
+
+		if sums[next] == 0 {
+			result = append(result, pick(balances, boundary^next))
+			boundary = next
+		}
+		mask = next
+	}
+
+	return result
+}
+
+// settleGroup settles a group of balances by letting the biggest debtor pay
+// the biggest creditor until everyone is even
+func settleGroup(group []models.PlayerBalance) []Transfer {
+	var debtors, creditors []models.PlayerBalance
+	for _, b := range group {
+		if b.Net < 0 {
+			debtors = append(debtors, models.PlayerBalance{PlayerID: b.PlayerID, Name: b.Name, Net: -b.Net})
+		} else if b.Net > 0 {
+			creditors = append(creditors, b)
+		}
+	}
+
+	byAmount := func(list []models.PlayerBalance) {
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].Net > list[j].Net
+		})
+	}
+
+	var transfers []Transfer
+	for len(debtors) > 0 && len(creditors) > 0 {
+		byAmount(debtors)
+		byAmount(creditors)
+
+		d, c := &debtors[0], &creditors[0]
+		amount := d.Net
+		if c.Net < amount {
+			amount = c.Net
+		}
+
+		transfers = append(transfers, Transfer{
+			FromID:   d.PlayerID,
+			FromName: d.Name,
+			ToID:     c.PlayerID,
+			ToName:   c.Name,
+			Amount:   amount,
+		})
+
+		d.Net -= amount
+		c.Net -= amount
+		if d.Net == 0 {
+			debtors = debtors[1:]
+		}
+		if c.Net == 0 {
+			creditors = creditors[1:]
+		}
+	}
+
+	return transfers
+}
+
+// pick returns the balances selected by the bits of mask
+func pick(balances []models.PlayerBalance, mask int) []models.PlayerBalance {
+	var picked []models.PlayerBalance
+	for i := range balances {
+		if mask&(1<<i) != 0 {
+			picked = append(picked, balances[i])
+		}
+	}
+	return picked
+}
+
+// bitIndex returns the position of the single set bit of v
+func bitIndex(v int) int {
+	i := 0
+	for v > 1 {
+		v >>= 1
+		i++
+	}
+	return i
+}
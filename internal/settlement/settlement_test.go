@@ -0,0 +1,117 @@
+package settlement
+
+import (
+	"testing"
+
+	"github.com/klausbreyer/pokerhans/internal/models"
+)
+
+func balances(nets ...models.Cents) []models.PlayerBalance {
+	result := make([]models.PlayerBalance, len(nets))
+	for i, net := range nets {
+		result[i] = models.PlayerBalance{PlayerID: i + 1, Net: net}
+	}
+	return result
+}
+
+// checkSettles verifies that applying the transfers brings every balance to zero
+func checkSettles(t *testing.T, input []models.PlayerBalance, transfers []Transfer) {
+	t.Helper()
+
+	remaining := make(map[int]models.Cents)
+	for _, b := range input {
+		remaining[b.PlayerID] = b.Net
+	}
+	for _, tr := range transfers {
+		if tr.Amount <= 0 {
+			t.Errorf("Expected positive transfer amount, got %d", tr.Amount)
+		}
+		remaining[tr.FromID] += tr.Amount
+		remaining[tr.ToID] -= tr.Amount
+	}
+	for id, net := range remaining {
+		if net != 0 {
+			t.Errorf("Player %d is left with %d after settling", id, net)
+		}
+	}
+}
+
+func TestSettleSimple(t *testing.T) {
+	input := balances(-1000, 600, 400)
+
+	transfers := Settle(input)
+	checkSettles(t, input, transfers)
+
+	if len(transfers) != 2 {
+		t.Errorf("Expected 2 transfers, got %d", len(transfers))
+	}
+}
+
+func TestSettleFindsZeroSumGroups(t *testing.T) {
+	// Pairs (1, 3) and (2, 4) cancel out exactly, so two transfers suffice,
+	// while a plain greedy matching would need three.
+	input := balances(-700, -500, 500, 700)
+	input[2], input[3] = input[3], input[2]
+
+	transfers := Settle(input)
+	checkSettles(t, input, transfers)
+
+	if len(transfers) != 2 {
+		t.Errorf("Expected 2 transfers, got %d: %v", len(transfers), transfers)
+	}
+}
+
+func TestSettleGroupedMinimum(t *testing.T) {
+	input := balances(-300, -200, 100, 400, -600, 600)
+
+	transfers := Settle(input)
+	checkSettles(t, input, transfers)
+
+	// {-600, 600} and {-300, -200, 100, 400} need 1 + 3 transfers
+	if len(transfers) != 4 {
+		t.Errorf("Expected 4 transfers, got %d: %v", len(transfers), transfers)
+	}
+}
+
+func TestSettleEmpty(t *testing.T) {
+	if transfers := Settle(balances(0, 0)); len(transfers) != 0 {
+		t.Errorf("Expected no transfers, got %v", transfers)
+	}
+	if transfers := Settle(nil); len(transfers) != 0 {
+		t.Errorf("Expected no transfers, got %v", transfers)
+	}
+}
+
+func TestSettleManyPlayers(t *testing.T) {
+	// More players than the exhaustive search handles still settle fully
+	var nets []models.Cents
+	for i := 0; i < exactLimit+4; i++ {
+		nets = append(nets, models.Cents((i%5)*100+100), -models.Cents((i%5)*100+100))
+	}
+	input := balances(nets...)
+
+	transfers := Settle(input)
+	checkSettles(t, input, transfers)
+
+	if len(transfers) >= len(input) {
+		t.Errorf("Expected fewer transfers than players, got %d for %d players", len(transfers), len(input))
+	}
+}
+
+func TestApplyPayments(t *testing.T) {
+	input := balances(-1000, 1000)
+	payments := []models.SettlementPayment{{FromID: 1, ToID: 2, Amount: 400}}
+
+	remaining := ApplyPayments(input, payments)
+	if remaining[0].Net != -600 || remaining[1].Net != 600 {
+		t.Errorf("Expected remaining balances -600 and 600, got %d and %d", remaining[0].Net, remaining[1].Net)
+	}
+	if input[0].Net != -1000 {
+		t.Errorf("Expected input balances to be left untouched")
+	}
+
+	transfers := Settle(remaining)
+	if len(transfers) != 1 || transfers[0].Amount != 600 {
+		t.Errorf("Expected a single open transfer of 600, got %v", transfers)
+	}
+}
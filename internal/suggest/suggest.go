@@ -0,0 +1,121 @@
+// Package suggest ranks the players still to visit by how much it is their
+// turn to host: mainly by the time since they last hosted, across seasons.
+package suggest
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Weights configures how much each factor adds to or takes from the score
+type Weights struct {
+	// PerWeek is added for every week since the candidate last hosted, or
+	// since they joined if they never hosted
+	PerWeek float64
+
+	// PerHosting is subtracted for every game the candidate hosted so far
+	PerHosting float64
+
+	// LastFinal is subtracted if the candidate hosted the last game of the
+	// previous season
+	LastFinal float64
+}
+
+// Candidate is a player who could host the next game
+type Candidate struct {
+	ID     int
+	Name   string
+	Joined time.Time
+
+	// LastHosted is the date of the candidate's last game as host, zero if
+	// they never hosted; LastSeason names its season
+	LastHosted time.Time
+	LastSeason string
+
+	TimesHosted int
+
+	// HostedLastFinal is set if the candidate hosted the last game of the
+	// previous season, named by FinalSeason
+	HostedLastFinal bool
+	FinalSeason     string
+}
+
+// Suggestion is a ranked candidate with the reasons for its score
+type Suggestion struct {
+	Candidate
+	Score   float64
+	Reasons []string
+}
+
+// Rank scores the candidates at the given time and returns them best first.
+// Ties are broken by name.
+func Rank(candidates []Candidate, w Weights, now time.Time) []Suggestion {
+	suggestions := make([]Suggestion, len(candidates))
+	for i, c := range candidates {
+		suggestions[i] = score(c, w, now)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+	return suggestions
+}
+
+// score computes the score of a candidate and explains it
+func score(c Candidate, w Weights, now time.Time) Suggestion {
+	s := Suggestion{Candidate: c}
+
+	if c.LastHosted.IsZero() {
+		weeks := weeksBetween(c.Joined, now)
+		s.Score += float64(weeks) * w.PerWeek
+		s.Reasons = append(s.Reasons, fmt.Sprintf("Never hosted, joined %s", ago(weeks)))
+	} else {
+		weeks := weeksBetween(c.LastHosted, now)
+		s.Score += float64(weeks) * w.PerWeek
+		s.Reasons = append(s.Reasons, fmt.Sprintf("Last hosted %s (%s)", ago(weeks), c.LastSeason))
+	}
+
+	if c.TimesHosted > 0 {
+		s.Score -= float64(c.TimesHosted) * w.PerHosting
+		s.Reasons = append(s.Reasons, fmt.Sprintf("Hosted %s in total", times(c.TimesHosted)))
+	}
+
+	if c.HostedLastFinal {
+		s.Score -= w.LastFinal
+		s.Reasons = append(s.Reasons, fmt.Sprintf("Hosted the last game of %s", c.FinalSeason))
+	}
+
+	return s
+}
+
+// weeksBetween returns the number of whole weeks from one time to another,
+// or 0 if from is not before to
+func weeksBetween(from, to time.Time) int {
+	if !from.Before(to) {
+		return 0
+	}
+	return int(to.Sub(from).Hours() / (24 * 7))
+}
+
+// ago describes a number of weeks in the past
+func ago(weeks int) string {
+	switch weeks {
+	case 0:
+		return "this week"
+	case 1:
+		return "1 week ago"
+	}
+	return fmt.Sprintf("%d weeks ago", weeks)
+}
+
+// times describes how often something happened
+func times(n int) string {
+	if n == 1 {
+		return "once"
+	}
+	return fmt.Sprintf("%d times", n)
+}
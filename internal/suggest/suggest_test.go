@@ -0,0 +1,63 @@
+package suggest
+
+import (
+	"testing"
+	"time"
+)
+
+var weights = Weights{PerWeek: 1, PerHosting: 4, LastFinal: 10}
+
+func weeksBefore(now time.Time, weeks int) time.Time {
+	return now.AddDate(0, 0, -7*weeks)
+}
+
+func TestRank(t *testing.T) {
+	now := time.Date(2025, time.October, 3, 0, 0, 0, 0, time.UTC)
+	candidates := []Candidate{
+		// 30 weeks, one hosting: 30 - 4 = 26
+		{ID: 1, Name: "Anna", LastHosted: weeksBefore(now, 30), LastSeason: "Spring 2025", TimesHosted: 1},
+		// 40 weeks, two hostings, last final: 40 - 8 - 10 = 22
+		{ID: 2, Name: "Max", LastHosted: weeksBefore(now, 40), LastSeason: "Winter 2024", TimesHosted: 2, HostedLastFinal: true, FinalSeason: "Winter 2024"},
+		// Joined 26 weeks ago: 26
+		{ID: 3, Name: "Jonas", Joined: weeksBefore(now, 26)},
+	}
+
+	ranked := Rank(candidates, weights, now)
+	expected := []struct {
+		id    int
+		score float64
+	}{{1, 26}, {3, 26}, {2, 22}}
+	for i, e := range expected {
+		if ranked[i].ID != e.id || ranked[i].Score != e.score {
+			t.Errorf("Rank %d: Expected player %d with %v, got player %d with %v",
+				i+1, e.id, e.score, ranked[i].ID, ranked[i].Score)
+		}
+	}
+
+	reasons := ranked[2].Reasons
+	if len(reasons) != 3 ||
+		reasons[0] != "Last hosted 40 weeks ago (Winter 2024)" ||
+		reasons[1] != "Hosted 2 times in total" ||
+		reasons[2] != "Hosted the last game of Winter 2024" {
+		t.Errorf("Unexpected reasons for Max: %q", reasons)
+	}
+
+	if reasons := ranked[1].Reasons; len(reasons) != 1 || reasons[0] != "Never hosted, joined 26 weeks ago" {
+		t.Errorf("Unexpected reasons for Jonas: %q", reasons)
+	}
+}
+
+func TestWeightsChangeOrder(t *testing.T) {
+	now := time.Date(2025, time.October, 3, 0, 0, 0, 0, time.UTC)
+	candidates := []Candidate{
+		{ID: 1, Name: "Anna", LastHosted: weeksBefore(now, 10), TimesHosted: 1},
+		{ID: 2, Name: "Max", LastHosted: weeksBefore(now, 20), TimesHosted: 5},
+	}
+
+	if ranked := Rank(candidates, Weights{PerWeek: 1}, now); ranked[0].ID != 2 {
+		t.Errorf("Expected Max first when only time counts, got %d", ranked[0].ID)
+	}
+	if ranked := Rank(candidates, Weights{PerWeek: 1, PerHosting: 5}, now); ranked[0].ID != 1 {
+		t.Errorf("Expected Anna first when hostings count, got %d", ranked[0].ID)
+	}
+}
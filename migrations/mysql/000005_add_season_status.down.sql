@@ -0,0 +1,2 @@
+-- Remove the season status column
+ALTER TABLE seasons DROP COLUMN status;
\ No newline at end of file
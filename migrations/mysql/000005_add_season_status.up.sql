@@ -0,0 +1,7 @@
+-- Add an explicit status to seasons instead of treating the highest ID as the latest
+ALTER TABLE seasons ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active';
+
+-- Keep the previous behaviour for existing data: only the latest season stays active
+UPDATE seasons s
+JOIN (SELECT MAX(id) AS max_id FROM seasons) latest ON s.id < latest.max_id
+SET s.status = 'archived';
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Remove the player active flag
+ALTER TABLE players DROP COLUMN active;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Allow players to be deactivated without losing their historical games
+ALTER TABLE players ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Drop the season_players table
+DROP TABLE IF EXISTS season_players;
\ No newline at end of file
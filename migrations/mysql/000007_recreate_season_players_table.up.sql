@@ -0,0 +1,24 @@
+-- Reintroduce season membership so only rostered players are expected to host
+CREATE TABLE IF NOT EXISTS season_players (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    season_id INT NOT NULL,
+    player_id INT NOT NULL,
+    FOREIGN KEY (season_id) REFERENCES seasons(id),
+    FOREIGN KEY (player_id) REFERENCES players(id),
+    UNIQUE KEY unique_season_player (season_id, player_id)
+);
+
+-- Everyone who hosted or placed in a season was part of it
+INSERT IGNORE INTO season_players (season_id, player_id)
+SELECT season_id, host_id FROM games
+UNION
+SELECT season_id, winner_id FROM games WHERE winner_id IS NOT NULL
+UNION
+SELECT season_id, second_place_id FROM games WHERE second_place_id IS NOT NULL;
+
+-- Active seasons keep showing every active player until their roster is edited
+INSERT IGNORE INTO season_players (season_id, player_id)
+SELECT s.id, p.id
+FROM seasons s
+CROSS JOIN players p
+WHERE s.status = 'active' AND p.active;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Drop the game_results table
+DROP TABLE IF EXISTS game_results;
\ No newline at end of file
@@ -0,0 +1,18 @@
+-- Record the full finishing order of every game
+CREATE TABLE IF NOT EXISTS game_results (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    game_id INT NOT NULL,
+    player_id INT NOT NULL,
+    position INT NOT NULL,
+    FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE,
+    FOREIGN KEY (player_id) REFERENCES players(id),
+    UNIQUE KEY unique_game_player (game_id, player_id),
+    UNIQUE KEY unique_game_position (game_id, position)
+);
+
+-- Backfill the results from the legacy winner and second place columns
+INSERT IGNORE INTO game_results (game_id, player_id, position)
+SELECT id, winner_id, 1 FROM games WHERE winner_id IS NOT NULL;
+
+INSERT IGNORE INTO game_results (game_id, player_id, position)
+SELECT id, second_place_id, 2 FROM games WHERE second_place_id IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Drop the game_ledger table
+DROP TABLE IF EXISTS game_ledger;
\ No newline at end of file
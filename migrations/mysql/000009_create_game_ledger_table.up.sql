@@ -0,0 +1,12 @@
+-- Track buy-ins, rebuys/add-ons and payouts per player and game, in integer cents
+CREATE TABLE IF NOT EXISTS game_ledger (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    game_id INT NOT NULL,
+    player_id INT NOT NULL,
+    buy_in_cents BIGINT NOT NULL DEFAULT 0,
+    rebuy_cents BIGINT NOT NULL DEFAULT 0,
+    payout_cents BIGINT NOT NULL DEFAULT 0,
+    FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE,
+    FOREIGN KEY (player_id) REFERENCES players(id),
+    UNIQUE KEY unique_game_ledger_player (game_id, player_id)
+);
\ No newline at end of file
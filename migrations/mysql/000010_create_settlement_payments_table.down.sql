@@ -0,0 +1,2 @@
+-- Drop the settlement_payments table
+DROP TABLE IF EXISTS settlement_payments;
\ No newline at end of file
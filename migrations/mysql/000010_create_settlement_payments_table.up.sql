@@ -0,0 +1,14 @@
+-- Record transfers that have been marked as settled
+CREATE TABLE IF NOT EXISTS settlement_payments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    season_id INT NOT NULL,
+    game_id INT NULL,
+    from_player_id INT NOT NULL,
+    to_player_id INT NOT NULL,
+    amount_cents BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (season_id) REFERENCES seasons(id),
+    FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE,
+    FOREIGN KEY (from_player_id) REFERENCES players(id),
+    FOREIGN KEY (to_player_id) REFERENCES players(id)
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- Remove the game constraints
+ALTER TABLE games DROP CHECK check_winner_not_second;
+ALTER TABLE games DROP INDEX unique_season_host;
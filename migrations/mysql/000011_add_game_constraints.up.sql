@@ -0,0 +1,8 @@
+-- Databases with double submissions or a winner who is also second place have
+-- to be cleaned up first with make fix-games
+
+-- Every player hosts at most once per season, so double submissions are rejected
+ALTER TABLE games ADD UNIQUE KEY unique_season_host (season_id, host_id);
+
+-- The winner and the second place must be different players
+ALTER TABLE games ADD CONSTRAINT check_winner_not_second CHECK (winner_id <> second_place_id);
@@ -0,0 +1,3 @@
+-- Remove the game status and date history
+DROP TABLE IF EXISTS game_date_changes;
+ALTER TABLE games DROP COLUMN status;
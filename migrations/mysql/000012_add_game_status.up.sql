@@ -0,0 +1,12 @@
+-- Games can be put in before they are played; existing games were all played
+ALTER TABLE games ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'played';
+
+-- Keep track of every change to the date of a game
+CREATE TABLE IF NOT EXISTS game_date_changes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    game_id INT NOT NULL,
+    old_date DATE NOT NULL,
+    new_date DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+-- Drop the game_attendance table
+DROP TABLE IF EXISTS game_attendance;
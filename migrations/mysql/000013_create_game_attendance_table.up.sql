@@ -0,0 +1,25 @@
+-- Record who said they would come to a game and who actually came
+CREATE TABLE IF NOT EXISTS game_attendance (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    game_id INT NOT NULL,
+    player_id INT NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE,
+    FOREIGN KEY (player_id) REFERENCES players(id),
+    UNIQUE KEY unique_game_attendee (game_id, player_id)
+);
+
+-- Everyone who hosted or took part in a played game attended it
+INSERT IGNORE INTO game_attendance (game_id, player_id, status)
+SELECT id, host_id, 'attended' FROM games WHERE status = 'played';
+
+INSERT IGNORE INTO game_attendance (game_id, player_id, status)
+SELECT gr.game_id, gr.player_id, 'attended'
+FROM game_results gr JOIN games g ON gr.game_id = g.id
+WHERE g.status = 'played';
+
+INSERT IGNORE INTO game_attendance (game_id, player_id, status)
+SELECT l.game_id, l.player_id, 'attended'
+FROM game_ledger l JOIN games g ON l.game_id = g.id
+WHERE g.status = 'played';
@@ -0,0 +1,4 @@
+-- Remove the season date range
+ALTER TABLE seasons DROP CHECK check_season_dates;
+ALTER TABLE seasons DROP COLUMN end_date;
+ALTER TABLE seasons DROP COLUMN start_date;
@@ -0,0 +1,16 @@
+-- Give seasons a date range; an empty end date means the season is open-ended
+ALTER TABLE seasons ADD COLUMN start_date DATE NULL AFTER name;
+ALTER TABLE seasons ADD COLUMN end_date DATE NULL AFTER start_date;
+
+-- Existing seasons start with their first game, or when they were created
+UPDATE seasons s
+LEFT JOIN (SELECT season_id, MIN(game_date) AS first_date FROM games GROUP BY season_id) g ON g.season_id = s.id
+SET s.start_date = COALESCE(g.first_date, DATE(s.created_at));
+
+-- Archived seasons end with their last game
+UPDATE seasons s
+JOIN (SELECT season_id, MAX(game_date) AS last_date FROM games GROUP BY season_id) g ON g.season_id = s.id
+SET s.end_date = g.last_date
+WHERE s.status = 'archived';
+
+ALTER TABLE seasons ADD CONSTRAINT check_season_dates CHECK (end_date IS NULL OR start_date IS NULL OR end_date >= start_date);
@@ -0,0 +1,8 @@
+-- Make seasons and players global again
+ALTER TABLE players DROP FOREIGN KEY fk_players_group;
+ALTER TABLE players DROP COLUMN group_id;
+
+ALTER TABLE seasons DROP FOREIGN KEY fk_seasons_group;
+ALTER TABLE seasons DROP COLUMN group_id;
+
+DROP TABLE IF EXISTS poker_groups;
@@ -0,0 +1,20 @@
+-- Groups own their seasons and players, so several poker circles can share
+-- one deployment. GROUPS is a reserved word in MySQL 8, hence the prefix.
+CREATE TABLE IF NOT EXISTS poker_groups (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    slug VARCHAR(64) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_group_slug (slug)
+);
+
+-- Existing seasons and players belong to the group that has used the app so far
+INSERT INTO poker_groups (id, slug, name) VALUES (1, 'hans', 'Poker Hans');
+
+ALTER TABLE seasons ADD COLUMN group_id INT NOT NULL DEFAULT 1 AFTER id;
+ALTER TABLE seasons ALTER COLUMN group_id DROP DEFAULT;
+ALTER TABLE seasons ADD CONSTRAINT fk_seasons_group FOREIGN KEY (group_id) REFERENCES poker_groups(id);
+
+ALTER TABLE players ADD COLUMN group_id INT NOT NULL DEFAULT 1 AFTER id;
+ALTER TABLE players ALTER COLUMN group_id DROP DEFAULT;
+ALTER TABLE players ADD CONSTRAINT fk_players_group FOREIGN KEY (group_id) REFERENCES poker_groups(id);
@@ -0,0 +1,5 @@
+-- Admins manage the group, members record games of the current season and
+-- viewers can only read. Everyone who could log in so far could change
+-- everything, so existing members become admins.
+ALTER TABLE group_members ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'member' AFTER member_id;
+UPDATE group_members SET role = 'admin';
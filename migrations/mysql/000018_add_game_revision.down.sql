@@ -0,0 +1,2 @@
+-- Remove the game revision
+ALTER TABLE games DROP COLUMN revision;
@@ -0,0 +1,7 @@
+-- Count every change to a game, so calendars can tell which version is newer
+ALTER TABLE games ADD COLUMN revision INT NOT NULL DEFAULT 0;
+
+-- Start from what the calendar sent so far: date changes, plus one if cancelled
+UPDATE games g
+SET g.revision = (SELECT COUNT(*) FROM game_date_changes c WHERE c.game_id = g.id)
+    + IF(g.status = 'cancelled', 1, 0);
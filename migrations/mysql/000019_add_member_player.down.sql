@@ -0,0 +1,4 @@
+-- Remove the link between members and players
+ALTER TABLE group_members DROP INDEX unique_group_player;
+ALTER TABLE group_members DROP FOREIGN KEY fk_group_members_player;
+ALTER TABLE group_members DROP COLUMN player_id;
@@ -0,0 +1,5 @@
+-- A member can be one of the players of a group, so they answer RSVPs for
+-- themselves. Each player belongs to at most one member.
+ALTER TABLE group_members ADD COLUMN player_id INT NULL AFTER role;
+ALTER TABLE group_members ADD CONSTRAINT fk_group_members_player FOREIGN KEY (player_id) REFERENCES players(id) ON DELETE SET NULL;
+ALTER TABLE group_members ADD UNIQUE KEY unique_group_player (group_id, player_id);
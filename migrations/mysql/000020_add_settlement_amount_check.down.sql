@@ -0,0 +1,2 @@
+-- Remove the settlement amount check. Payments deleted by the up migration are not restored.
+ALTER TABLE settlement_payments DROP CHECK check_settlement_amount_positive;
@@ -0,0 +1,5 @@
+-- Settled transfers always move money. The site never stored empty or
+-- negative ones; remove any that were entered by hand so the check can be added.
+DELETE FROM settlement_payments WHERE amount_cents <= 0;
+
+ALTER TABLE settlement_payments ADD CONSTRAINT check_settlement_amount_positive CHECK (amount_cents > 0);